@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+)
+
+// NewGraphFromArrow creates a graph from the relationships in an
+// in-memory Arrow table. The source and target columns are looked up
+// by name and must hold strings. Rows where either value is null are
+// skipped.
+func NewGraphFromArrow(table array.Table, sourceCol, targetCol string) (*Graph, error) {
+	graph, _, err := LoadArrow(table, sourceCol, targetCol, LoadOptions{})
+	return graph, err
+}
+
+// LoadArrow creates a graph from an Arrow table like NewGraphFromArrow,
+// applying the load options to every row. Rows with a null value are
+// counted as skipped.
+func LoadArrow(table array.Table, sourceCol, targetCol string, opts LoadOptions) (*Graph, LoadResult, error) {
+	result := LoadResult{}
+	sources, err := arrowStringColumn(table, sourceCol)
+	if err != nil {
+		return nil, result, err
+	}
+	targets, err := arrowStringColumn(table, targetCol)
+	if err != nil {
+		return nil, result, err
+	}
+	graph := &Graph{}
+	s, t := newArrowStringCursor(sources), newArrowStringCursor(targets)
+	for i := int64(0); i < table.NumRows(); i++ {
+		result.Rows++
+		source, sourceOk := s.next()
+		target, targetOk := t.next()
+		if !sourceOk || !targetOk {
+			result.Skipped++
+			opts.logf("skipping row %d with null source or target", result.Rows)
+		} else if err := opts.insert(graph, source, target, &result); err != nil {
+			return nil, result, err
+		}
+		opts.progress(result.Rows, false)
+	}
+	opts.progress(result.Rows, true)
+	return graph, result, nil
+}
+
+// Returns the chunks of the named string column in the table.
+func arrowStringColumn(table array.Table, name string) ([]*array.String, error) {
+	indices := table.Schema().FieldIndices(name)
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("missing column %s", name)
+	}
+	column := table.Column(indices[0])
+	if column.DataType().ID() != arrow.STRING {
+		return nil, fmt.Errorf("column %s has type %s, expected utf8", name, column.DataType())
+	}
+	chunks := []*array.String{}
+	for _, chunk := range column.Data().Chunks() {
+		chunks = append(chunks, chunk.(*array.String))
+	}
+	return chunks, nil
+}
+
+// arrowStringCursor iterates the values of a chunked string column
+// row by row. Columns of the same table can be chunked differently,
+// so each column keeps its own position.
+type arrowStringCursor struct {
+	chunks []*array.String
+	chunk  int
+	row    int
+}
+
+func newArrowStringCursor(chunks []*array.String) *arrowStringCursor {
+	return &arrowStringCursor{chunks: chunks}
+}
+
+// Returns the next value in the column and false if it is null.
+func (c *arrowStringCursor) next() (string, bool) {
+	for c.row >= c.chunks[c.chunk].Len() {
+		c.chunk++
+		c.row = 0
+	}
+	chunk, row := c.chunks[c.chunk], c.row
+	c.row++
+	if chunk.IsNull(row) {
+		return "", false
+	}
+	return chunk.Value(row), true
+}
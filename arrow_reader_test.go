@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// TestArrow builds a small Arrow table in memory and checks the
+// constructed graph.
+func TestArrow(t *testing.T) {
+	mem := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "source", Type: arrow.BinaryTypes.String},
+		{Name: "target", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.StringBuilder).AppendValues(
+		[]string{"stg_orders", "stg_orders", "stg_payments", "stg_orders", "gsheets.goals"}, nil)
+	builder.Field(1).(*array.StringBuilder).AppendValues(
+		[]string{"dim_customers", "fct_orders", "fct_orders", "fct_orders", ""},
+		[]bool{true, true, true, true, false})
+	record := builder.NewRecord()
+	defer record.Release()
+
+	table := array.NewTableFromRecords(schema, []array.Record{record})
+	defer table.Release()
+
+	graph, err := NewGraphFromArrow(table, "source", "target")
+	if err != nil {
+		t.Fatalf("Unable to read arrow table - %v", err)
+	}
+
+	// assert number of nodes, the null target is skipped
+	if len(graph.nodes) != 4 {
+		t.Fatalf(`Node count mismatch. Expected %d, Found %d`, 4, len(graph.nodes))
+	}
+	node := graph.nodes["fct_orders"]
+	sort.Strings(node.upstream)
+	upstream := strings.Join(node.upstream, ",")
+	expectedUpstream := "stg_orders,stg_payments"
+	if upstream != expectedUpstream {
+		t.Fatalf(`Upstream relations mismatch. Expected %v, Found %v`, expectedUpstream, upstream)
+	}
+
+	// the load options are applied to every row
+	drop := func(from, to string) (string, string, bool) { return from, to, from != "stg_payments" }
+	graph, result, err := LoadArrow(table, "source", "target", LoadOptions{EdgeTransform: drop})
+	if err != nil {
+		t.Fatalf("Unable to read arrow table - %v", err)
+	}
+	if result.Rows != 5 || result.Skipped != 1 || result.Dropped != 1 || len(graph.nodes) != 3 {
+		t.Fatalf("Load result mismatch. Expected %v and 3 nodes, Found %v and %d nodes", LoadResult{Rows: 5, Skipped: 1, Dropped: 1}, result, len(graph.nodes))
+	}
+
+	// unknown columns are an error
+	if _, err := NewGraphFromArrow(table, "from", "target"); err == nil {
+		t.Fatalf("Expected error for missing column")
+	}
+}
@@ -0,0 +1,22 @@
+package graph
+
+import "sync/atomic"
+
+// AtomicGraph holds a graph that can be swapped atomically. Readers
+// keep querying the graph they loaded while a replacement is built
+// and stored, so a query never sees a partially constructed graph.
+type AtomicGraph struct {
+	ptr atomic.Pointer[Graph]
+}
+
+// Load returns the current graph. Returns nil if no graph has been
+// stored yet.
+func (a *AtomicGraph) Load() *Graph {
+	return a.ptr.Load()
+}
+
+// Store replaces the current graph. The graph must be fully built
+// and must not be modified after it is stored.
+func (a *AtomicGraph) Store(g *Graph) {
+	a.ptr.Store(g)
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestAtomicGraphSwap swaps graphs while readers are querying and
+// checks that every reader sees a complete graph. Run with -race.
+func TestAtomicGraphSwap(t *testing.T) {
+	// build a chain graph of the given length where node 0 has
+	// length downstream nodes.
+	build := func(length int) *Graph {
+		graph := &Graph{}
+		for i := 0; i < length; i++ {
+			graph.insert(strconv.Itoa(i), strconv.Itoa(i+1))
+		}
+		return graph
+	}
+
+	holder := &AtomicGraph{}
+	holder.Store(build(10))
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 8)
+	done := make(chan struct{})
+	for r := 0; r < 8; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				graph := holder.Load()
+				downstream, err := graph.downstream([]string{"0"})
+				if err != nil {
+					errs <- err.Error()
+					return
+				}
+				// a consistent graph has exactly one node more than
+				// the downstream of its first node.
+				if len(downstream) != len(graph.nodes)-1 {
+					errs <- "inconsistent graph " + strconv.Itoa(len(downstream))
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 50; i++ {
+		holder.Store(build(10 + i))
+	}
+	close(done)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("Reader saw an inconsistent graph - %v", err)
+	}
+	if len(holder.Load().nodes) != 61 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 61, len(holder.Load().nodes))
+	}
+}
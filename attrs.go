@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"sort"
+	"time"
+)
+
+// EdgeAttrs holds the optional attributes recorded for an edge.
+type EdgeAttrs struct {
+	// Kinds lists the distinct kinds the edge was inserted with.
+	Kinds []string
+	// ValidFrom and ValidTo bound the time the edge is valid. A zero
+	// time leaves that side of the interval open.
+	ValidFrom time.Time
+	ValidTo   time.Time
+	// Origins lists the distinct systems the edge was loaded from.
+	Origins []string
+	// Cost is the cost of following the edge, such as the time to
+	// refresh its target. Edges without attributes cost nothing.
+	Cost float64
+}
+
+// Returns a deep copy of the attributes.
+func (a *EdgeAttrs) clone() *EdgeAttrs {
+	return &EdgeAttrs{
+		Kinds:     append([]string{}, a.Kinds...),
+		ValidFrom: a.ValidFrom,
+		ValidTo:   a.ValidTo,
+		Origins:   append([]string{}, a.Origins...),
+		Cost:      a.Cost,
+	}
+}
+
+// KindConflict reports an edge that was inserted with two kinds that
+// conflict with each other.
+type KindConflict struct {
+	Edge  Edge
+	Kinds [2]string
+}
+
+// Returns the attributes of the edge, creating them if they do not
+// exist.
+func (g *Graph) edgeAttrs(from, to string) *EdgeAttrs {
+	if g.attrs == nil {
+		g.attrs = make(map[Edge]*EdgeAttrs)
+	}
+	edge := Edge{From: from, To: to}
+	attrs, ok := g.attrs[edge]
+	if !ok {
+		attrs = &EdgeAttrs{}
+		g.attrs[edge] = attrs
+	}
+	return attrs
+}
+
+// Copies the attributes of the edge from another graph, if it has any.
+func (g *Graph) copyAttrs(other *Graph, edge Edge) {
+	if attrs, ok := other.attrs[edge]; ok {
+		*g.edgeAttrs(edge.From, edge.To) = *attrs.clone()
+	}
+}
+
+// InsertKind inserts the relation with the given kind. Inserting the
+// same pair again with another kind keeps a single edge that records
+// both kinds.
+func (g *Graph) InsertKind(from, to, kind string) {
+	g.insert(from, to)
+	attrs := g.edgeAttrs(from, to)
+	if !contains(attrs.Kinds, kind) {
+		attrs.Kinds = append(attrs.Kinds, kind)
+	}
+}
+
+// InsertOrigin inserts the relation and records the system it was
+// loaded from. Inserting the same pair from another system keeps a
+// single edge that records both origins.
+func (g *Graph) InsertOrigin(from, to, origin string) {
+	g.insert(from, to)
+	attrs := g.edgeAttrs(from, to)
+	if !contains(attrs.Origins, origin) {
+		attrs.Origins = append(attrs.Origins, origin)
+	}
+}
+
+// EdgesFromSource returns the edges recorded with the given origin,
+// sorted by source and target.
+func (g *Graph) EdgesFromSource(origin string) []Edge {
+	edges := []Edge{}
+	for edge, attrs := range g.attrs {
+		if contains(attrs.Origins, origin) {
+			edges = append(edges, edge)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// MergeParallelEdgesByKind checks the edges that were inserted with
+// several kinds. Parallel edges between the same pair are always
+// merged into a single edge, so the kinds of each edge are compared
+// pairwise with the conflicting predicate and every conflicting pair
+// is returned. Conflicts are sorted by edge and then by kinds.
+func (g *Graph) MergeParallelEdgesByKind(conflicting func(a, b string) bool) []KindConflict {
+	conflicts := []KindConflict{}
+	for edge, attrs := range g.attrs {
+		kinds := append([]string{}, attrs.Kinds...)
+		sort.Strings(kinds)
+		for i := 0; i < len(kinds); i++ {
+			for j := i + 1; j < len(kinds); j++ {
+				if conflicting(kinds[i], kinds[j]) || conflicting(kinds[j], kinds[i]) {
+					conflicts = append(conflicts, KindConflict{Edge: edge, Kinds: [2]string{kinds[i], kinds[j]}})
+				}
+			}
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		a, b := conflicts[i], conflicts[j]
+		if a.Edge != b.Edge {
+			if a.Edge.From != b.Edge.From {
+				return a.Edge.From < b.Edge.From
+			}
+			return a.Edge.To < b.Edge.To
+		}
+		if a.Kinds[0] != b.Kinds[0] {
+			return a.Kinds[0] < b.Kinds[0]
+		}
+		return a.Kinds[1] < b.Kinds[1]
+	})
+	return conflicts
+}
@@ -0,0 +1,31 @@
+package graph
+
+import "testing"
+
+// TestMergeParallelEdgesByKind inserts edges with several kinds and
+// checks that only the conflicting kinds are reported.
+func TestMergeParallelEdgesByKind(t *testing.T) {
+	graph := &Graph{}
+	graph.InsertKind("stg_orders", "fct_orders", "depends_on")
+	graph.InsertKind("stg_orders", "fct_orders", "excludes")
+	graph.InsertKind("stg_payments", "fct_orders", "depends_on")
+	graph.InsertKind("stg_payments", "fct_orders", "reads")
+	graph.InsertKind("stg_payments", "fct_orders", "depends_on")
+
+	// parallel edges are merged into one
+	if len(graph.nodes["fct_orders"].upstream) != 2 {
+		t.Fatalf("Upstream count mismatch. Expected %d, Found %d", 2, len(graph.nodes["fct_orders"].upstream))
+	}
+
+	conflicting := func(a, b string) bool {
+		return a == "depends_on" && b == "excludes"
+	}
+	conflicts := graph.MergeParallelEdgesByKind(conflicting)
+	expected := KindConflict{
+		Edge:  Edge{From: "stg_orders", To: "fct_orders"},
+		Kinds: [2]string{"depends_on", "excludes"},
+	}
+	if len(conflicts) != 1 || conflicts[0] != expected {
+		t.Fatalf("Conflicts mismatch. Expected %v, Found %v", []KindConflict{expected}, conflicts)
+	}
+}
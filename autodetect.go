@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+)
+
+// parquetMagic starts every parquet file.
+var parquetMagic = []byte("PAR1")
+
+// NewGraphFromReaderAutodetect creates a graph from input of unknown
+// format by looking at its first bytes. Input starting with the
+// parquet magic PAR1 is read as parquet, input starting with "[" as
+// JSON records (see NewGraphFromJSONRecords), input starting with "{"
+// as JSON lines (see NewGraphFromJSONLines), and anything else as CSV
+// with a header row. Leading whitespace is ignored when looking for
+// JSON. Parquet input is buffered in memory since it must be read
+// from its end.
+func NewGraphFromReaderAutodetect(r io.Reader) (*Graph, error) {
+	graph, _, err := LoadReaderAutodetect(r, LoadOptions{})
+	return graph, err
+}
+
+// LoadReaderAutodetect reads input of unknown format like
+// NewGraphFromReaderAutodetect, passing the load options to the loader
+// of the detected format.
+func LoadReaderAutodetect(r io.Reader, opts LoadOptions) (*Graph, LoadResult, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(parquetMagic))
+	if err != nil && err != io.EOF {
+		return nil, LoadResult{}, err
+	}
+	if bytes.Equal(head, parquetMagic) {
+		content, err := io.ReadAll(br)
+		if err != nil {
+			return nil, LoadResult{}, err
+		}
+		columns, err := newParquetColumns(buffer.NewBufferFileFromBytes(content))
+		if err != nil {
+			return nil, LoadResult{}, err
+		}
+		defer columns.close()
+		return loadParquetColumns(columns, opts)
+	}
+
+	// peek past leading whitespace for the first significant byte
+	first := byte(0)
+	for n := 1; n <= br.Size(); n++ {
+		peeked, err := br.Peek(n)
+		if len(peeked) < n {
+			if err != nil && err != io.EOF {
+				return nil, LoadResult{}, err
+			}
+			break
+		}
+		if c := peeked[n-1]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			first = c
+			break
+		}
+	}
+	switch first {
+	case '[':
+		return LoadJSONRecords(br, opts)
+	case '{':
+		return LoadJSONLines(br, opts)
+	}
+	return loadCsv(br, opts)
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestNewGraphFromReaderAutodetect feeds every supported format as a
+// raw reader and asserts that it is detected and parsed.
+func TestNewGraphFromReaderAutodetect(t *testing.T) {
+	edges := "jaffle_shop.orders->stg_orders,stg_orders->fct_orders"
+	tests := []struct {
+		format   string
+		input    string
+		expected string
+	}{
+		{"csv", "source,target\njaffle_shop.orders,stg_orders\nstg_orders,fct_orders\n", edges},
+		{"json", `[{"source": "jaffle_shop.orders", "targets": ["stg_orders"]}, {"source": "stg_orders", "targets": ["fct_orders"]}]`, edges},
+		{"indented json", "\n  [{\"source\": \"jaffle_shop.orders\", \"targets\": [\"stg_orders\"]},\n   {\"source\": \"stg_orders\", \"targets\": [\"fct_orders\"]}]", edges},
+		{"json lines", "{\"source\": \"jaffle_shop.orders\", \"target\": \"stg_orders\"}\n{\"source\": \"stg_orders\", \"target\": \"fct_orders\"}\n", edges},
+		{"empty", "", ""},
+	}
+	for _, test := range tests {
+		graph, err := NewGraphFromReaderAutodetect(strings.NewReader(test.input))
+		if err != nil {
+			t.Fatalf("Unable to read %s input - %v", test.format, err)
+		}
+		if result := edgeList(graph); result != test.expected {
+			t.Fatalf("Edge mismatch for %s input. Expected %v, Found %v", test.format, test.expected, result)
+		}
+	}
+
+	// input that is neither parquet nor JSON and too narrow for CSV is
+	// an error rather than a panic
+	if _, err := NewGraphFromReaderAutodetect(strings.NewReader("source\nstg_orders\n")); err == nil {
+		t.Fatalf("Expected error for single column input")
+	}
+
+	// parquet is detected by its magic bytes
+	content, err := os.ReadFile("synq-lineage.parquet")
+	if err != nil {
+		t.Fatalf("Unable to read input file - %v", err)
+	}
+	graph, err := NewGraphFromReaderAutodetect(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Unable to read parquet input - %v", err)
+	}
+	csvGraph, err := NewGraphFromCsv("synq-lineage.csv")
+	if err != nil {
+		t.Fatalf("Unable to read input file - %v", err)
+	}
+	if equal, details := graph.Compare(csvGraph); !equal {
+		t.Fatalf("Expected parquet input to match the CSV fixture, Found differences\n%s", details)
+	}
+}
@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syntheticEdges returns a layered edge list with duplicates, where
+// every node feeds a few nodes of the next layer.
+func syntheticEdges(layers, width int) []Edge {
+	edges := []Edge{}
+	for l := 0; l < layers-1; l++ {
+		for i := 0; i < width; i++ {
+			from := fmt.Sprintf("layer%d.model%d", l, i)
+			for _, j := range []int{i, (i * 7) % width, (i + 1) % width, i} {
+				edges = append(edges, Edge{From: from, To: fmt.Sprintf("layer%d.model%d", l+1, j)})
+			}
+		}
+	}
+	return edges
+}
+
+// TestBuildFromEdgesParallel checks that the parallel build equals
+// inserting the edges one by one, including the relation order.
+func TestBuildFromEdgesParallel(t *testing.T) {
+	edges := syntheticEdges(5, 200)
+	serial := &Graph{}
+	for _, edge := range edges {
+		serial.insert(edge.From, edge.To)
+	}
+
+	for _, workers := range []int{1, 4} {
+		parallel := BuildFromEdgesParallel(edges, workers)
+		if equal, details := parallel.Compare(serial); !equal {
+			t.Fatalf("Graph mismatch with %d workers.\n%s", workers, details)
+		}
+		for path, node := range serial.nodes {
+			found := parallel.nodes[path]
+			if strings.Join(found.downstream, ",") != strings.Join(node.downstream, ",") {
+				t.Fatalf("Downstream relations mismatch for %s. Expected %v, Found %v", path, node.downstream, found.downstream)
+			}
+			if strings.Join(found.upstream, ",") != strings.Join(node.upstream, ",") {
+				t.Fatalf("Upstream relations mismatch for %s. Expected %v, Found %v", path, node.upstream, found.upstream)
+			}
+		}
+	}
+}
+
+// TestGraphBuilder adds edges from several goroutines, freezes the
+// graph and queries it concurrently. Run with -race to check locking.
+func TestGraphBuilder(t *testing.T) {
+	edges := syntheticEdges(4, 50)
+	builder := &GraphBuilder{}
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(edges); i += 4 {
+				if err := builder.AddEdge(edges[i].From, edges[i].To); err != nil {
+					t.Errorf("Error adding edge - %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	graph := builder.Freeze()
+	serial := &Graph{}
+	for _, edge := range edges {
+		serial.insert(edge.From, edge.To)
+	}
+	if equal, details := graph.Compare(serial); !equal {
+		t.Fatalf("Graph mismatch.\n%s", details)
+	}
+	if builder.Freeze() != graph {
+		t.Fatalf("Expected Freeze to return the same graph")
+	}
+	if err := builder.AddEdge("stg_orders", "fct_orders"); !errors.Is(err, ErrBuilderFrozen) {
+		t.Fatalf("Error mismatch. Expected %v, Found %v", ErrBuilderFrozen, err)
+	}
+
+	// the frozen graph is queried from several goroutines
+	expected, err := serial.downstream([]string{"layer0.model0"})
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	sort.Strings(expected)
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			downstream, err := graph.downstream([]string{"layer0.model0"})
+			if err != nil {
+				t.Errorf("Error getting downstream - %v", err)
+				return
+			}
+			sort.Strings(downstream)
+			if strings.Join(downstream, ",") != strings.Join(expected, ",") {
+				t.Errorf("Downstream mismatch. Expected %v, Found %v", expected, downstream)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkBuildSerial inserts a large synthetic edge list one edge
+// at a time.
+func BenchmarkBuildSerial(b *testing.B) {
+	edges := syntheticEdges(20, 5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		graph := &Graph{}
+		for _, edge := range edges {
+			graph.insert(edge.From, edge.To)
+		}
+	}
+}
+
+// BenchmarkBuildParallel builds the same edge list as
+// BenchmarkBuildSerial with BuildFromEdgesParallel.
+func BenchmarkBuildParallel(b *testing.B) {
+	edges := syntheticEdges(20, 5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BuildFromEdgesParallel(edges, 8)
+	}
+}
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoGraph is returned by a CachingGraph queried before a graph has
+// been stored in its AtomicGraph.
+var ErrNoGraph = errors.New("no graph stored")
+
+// cacheEntry holds a cached query result and when it expires.
+type cacheEntry struct {
+	paths   []string
+	expires time.Time
+}
+
+// minCacheSweep is the number of entries at which a CachingGraph first
+// sweeps out expired entries.
+const minCacheSweep = 64
+
+// CachingGraph caches downstream queries over a graph that is replaced
+// in the background, so that popular queries are answered from memory
+// and refreshed once their entries expire. Expired entries are swept
+// out whenever the number of entries doubles since the last sweep, so
+// one-off queries do not grow the cache without bound. Failed queries
+// are not cached. It is safe for concurrent use.
+type CachingGraph struct {
+	graph   *AtomicGraph
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	// sweepAt is the number of entries that triggers the next sweep.
+	sweepAt int
+	// query runs a single traversal and now returns the current time.
+	// Both are replaced in tests.
+	query func(paths []string) ([]string, error)
+	now   func() time.Time
+}
+
+// NewCachingGraph returns a cache over the graph currently stored in
+// the AtomicGraph. Queries read the graph stored at the time they run.
+func NewCachingGraph(graph *AtomicGraph) *CachingGraph {
+	c := &CachingGraph{
+		graph:   graph,
+		entries: make(map[string]cacheEntry),
+		sweepAt: minCacheSweep,
+		now:     time.Now,
+	}
+	c.query = func(paths []string) ([]string, error) {
+		graph := c.graph.Load()
+		if graph == nil {
+			return nil, ErrNoGraph
+		}
+		return graph.downstream(paths)
+	}
+	return c
+}
+
+// Returns the cache key of the paths, which ignores their order and
+// duplicates.
+func cacheKey(paths []string) string {
+	sorted := append([]string{}, paths...)
+	sort.Strings(sorted)
+	unique := sorted[:0]
+	for i, path := range sorted {
+		if i == 0 || path != sorted[i-1] {
+			unique = append(unique, path)
+		}
+	}
+	return strings.Join(unique, "\x00")
+}
+
+// DownstreamCached gets all the downstream nodes for the given paths,
+// returning the cached result if the same paths were queried less than
+// ttl ago. Otherwise the query is run on the current graph and cached
+// for ttl. Returns ErrNoGraph if no graph has been stored yet.
+func (c *CachingGraph) DownstreamCached(paths []string, ttl time.Duration) ([]string, error) {
+	key := cacheKey(paths)
+	now := c.now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && now.After(entry.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if ok {
+		return append([]string{}, entry.paths...), nil
+	}
+
+	result, err := c.query(paths)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{paths: result, expires: now.Add(ttl)}
+	if len(c.entries) >= c.sweepAt {
+		c.sweep(now)
+	}
+	c.mu.Unlock()
+	return append([]string{}, result...), nil
+}
+
+// Deletes the entries expired at now and sets the size of the next
+// sweep to twice the entries left. The caller must hold the lock.
+func (c *CachingGraph) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	c.sweepAt = 2 * len(c.entries)
+	if c.sweepAt < minCacheSweep {
+		c.sweepAt = minCacheSweep
+	}
+}
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDownstreamCached asserts that a second call within the TTL is
+// not recomputed and that a call after expiry is.
+func TestDownstreamCached(t *testing.T) {
+	graph := &AtomicGraph{}
+	cache := NewCachingGraph(graph)
+
+	// querying before a graph is stored is an error, and is not cached
+	if _, err := cache.DownstreamCached([]string{"stg_orders"}, time.Minute); !errors.Is(err, ErrNoGraph) {
+		t.Fatalf("Expected ErrNoGraph, Found %v", err)
+	}
+	graph.Store(jaffleShopGraph())
+
+	computed := 0
+	query := cache.query
+	cache.query = func(paths []string) ([]string, error) {
+		computed++
+		return query(paths)
+	}
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	downstream, err := cache.DownstreamCached([]string{"stg_payments", "stg_orders"}, time.Minute)
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	sort.Strings(downstream)
+	expected := "dim_customers,fct_orders,weekly_jaffle_metrics"
+	if result := strings.Join(downstream, ","); result != expected {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", expected, result)
+	}
+
+	// the same seeds in another order within the TTL are cached
+	now = now.Add(30 * time.Second)
+	if _, err := cache.DownstreamCached([]string{"stg_orders", "stg_payments"}, time.Minute); err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	if computed != 1 {
+		t.Fatalf("Compute count mismatch. Expected %d, Found %d", 1, computed)
+	}
+
+	// after expiry the query runs on the current graph
+	updated := jaffleShopGraph()
+	updated.insert("stg_payments", "fct_payments")
+	graph.Store(updated)
+	now = now.Add(time.Minute)
+	downstream, err = cache.DownstreamCached([]string{"stg_orders", "stg_payments"}, time.Minute)
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	if computed != 2 || len(downstream) != 4 {
+		t.Fatalf("Expected a recomputed result with 4 nodes, Found %d computations and %v", computed, downstream)
+	}
+
+	if _, err := cache.DownstreamCached([]string{"missing"}, time.Minute); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+
+	// one-off queries that expire are swept out as new ones arrive
+	for i := 0; i < 10*minCacheSweep; i++ {
+		now = now.Add(time.Minute)
+		seed := "raw.event_" + strconv.Itoa(i)
+		graph.Load().getOrCreate(seed)
+		if _, err := cache.DownstreamCached([]string{seed}, time.Second); err != nil {
+			t.Fatalf("Error getting downstream - %v", err)
+		}
+	}
+	if len(cache.entries) >= minCacheSweep {
+		t.Fatalf("Expected expired entries to be swept, Found %d entries", len(cache.entries))
+	}
+}
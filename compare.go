@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// maxCompareDetails caps the number of differences described by
+// Compare.
+const maxCompareDetails = 10
+
+// Equal reports whether both graphs have the same nodes and edges,
+// regardless of the order in which relations were inserted.
+func (g *Graph) Equal(other *Graph) bool {
+	equal, _ := g.Compare(other)
+	return equal
+}
+
+// Compare reports whether both graphs have the same nodes and edges,
+// regardless of the order in which relations were inserted. When they
+// differ, details describes the first differences in path order:
+// nodes and edges only in the graph are marked "-" and those only in
+// the other graph "+".
+func (g *Graph) Compare(other *Graph) (equal bool, details string) {
+	diff := Diff(g, other)
+	diffs := []string{}
+	for _, path := range diff.RemovedNodes {
+		diffs = append(diffs, fmt.Sprintf("- node %s", path))
+	}
+	for _, path := range diff.AddedNodes {
+		diffs = append(diffs, fmt.Sprintf("+ node %s", path))
+	}
+	for _, edge := range diff.RemovedEdges {
+		diffs = append(diffs, fmt.Sprintf("- edge %s -> %s", edge.From, edge.To))
+	}
+	for _, edge := range diff.AddedEdges {
+		diffs = append(diffs, fmt.Sprintf("+ edge %s -> %s", edge.From, edge.To))
+	}
+
+	if diff.Empty() {
+		return true, ""
+	}
+	if len(diffs) > maxCompareDetails {
+		more := len(diffs) - maxCompareDetails
+		diffs = append(diffs[:maxCompareDetails], fmt.Sprintf("... and %d more", more))
+	}
+	return false, strings.Join(diffs, "\n")
+}
+
+// EqualEdges reports whether the edges of the graph are exactly the
+// expected edges. missing holds the expected edges absent from the
+// graph and extra the edges of the graph that were not expected, both
+// sorted. Isolated nodes are not compared.
+func (g *Graph) EqualEdges(expected []Edge) (equal bool, missing []Edge, extra []Edge) {
+	want := &Graph{}
+	for _, edge := range expected {
+		want.insert(edge.From, edge.To)
+	}
+	diff := Diff(g, want)
+	return len(diff.AddedEdges) == 0 && len(diff.RemovedEdges) == 0, diff.AddedEdges, diff.RemovedEdges
+}
+
+// Fingerprint returns a hex encoded SHA-256 hash over the sorted nodes
+// and edges of the graph. Graphs that are Equal have the same
+// fingerprint regardless of insertion order, and any difference in
+// nodes or edges changes it. Edge attributes are not included.
+func (g *Graph) Fingerprint() string {
+	h := sha256.New()
+	// paths are length prefixed so that no two sets hash the same input
+	for _, path := range g.sortedPaths() {
+		fmt.Fprintf(h, "n%d:%s", len(path), path)
+	}
+	for _, edge := range g.edges() {
+		fmt.Fprintf(h, "e%d:%s%d:%s", len(edge.From), edge.From, len(edge.To), edge.To)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
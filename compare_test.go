@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestCompare asserts that graphs built in a different order are
+// equal and that the details name an added edge.
+func TestCompare(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	// the same edges inserted in reverse order
+	reversed := &Graph{}
+	edges := graph.edges()
+	for i := len(edges) - 1; i >= 0; i-- {
+		reversed.insert(edges[i].From, edges[i].To)
+	}
+	if equal, details := graph.Compare(reversed); !equal {
+		t.Fatalf("Expected graphs to be equal, Found differences\n%s", details)
+	}
+	if !graph.Equal(reversed) {
+		t.Fatalf("Expected graphs to be equal")
+	}
+
+	reversed.insert("stg_payments", "dim_customers")
+	equal, details := graph.Compare(reversed)
+	if equal {
+		t.Fatalf("Expected graphs to differ")
+	}
+	expected := "+ edge stg_payments -> dim_customers"
+	if details != expected {
+		t.Fatalf("Details mismatch. Expected %q, Found %q", expected, details)
+	}
+
+	reversed.insert("stg_payments", "fct_payments")
+	_, details = graph.Compare(reversed)
+	if !strings.Contains(details, "+ node fct_payments") {
+		t.Fatalf("Expected details to name the added node, Found %q", details)
+	}
+}
+
+// TestEqualEdges asserts that an expected relation absent from the
+// graph is reported as missing.
+func TestEqualEdges(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	equal, missing, extra := graph.EqualEdges(graph.edges())
+	if !equal || len(missing) != 0 || len(extra) != 0 {
+		t.Fatalf("Expected edges to match, Found missing %v and extra %v", missing, extra)
+	}
+
+	graph.removeEdge("stg_payments", "fct_orders")
+	graph.insert("stg_payments", "fct_payments")
+	equal, missing, extra = graph.EqualEdges(jaffleShopGraph().edges())
+	if equal {
+		t.Fatalf("Expected edges to differ")
+	}
+	if len(missing) != 1 || missing[0] != (Edge{From: "stg_payments", To: "fct_orders"}) {
+		t.Fatalf("Missing edges mismatch. Found %v", missing)
+	}
+	if len(extra) != 1 || extra[0] != (Edge{From: "stg_payments", To: "fct_payments"}) {
+		t.Fatalf("Extra edges mismatch. Found %v", extra)
+	}
+}
+
+// TestFingerprint asserts that a graph and its JSON round-trip have
+// the same fingerprint and that adding an edge changes it.
+func TestFingerprint(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	records := []JSONRecord{}
+	for path, adjacency := range graph.AdjacencyDown() {
+		records = append(records, JSONRecord{Source: path, Targets: adjacency})
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(records); err != nil {
+		t.Fatalf("Unable to encode records - %v", err)
+	}
+	roundTrip, err := NewGraphFromJSONRecords(&buf)
+	if err != nil {
+		t.Fatalf("Unable to read records - %v", err)
+	}
+	if graph.Fingerprint() != roundTrip.Fingerprint() {
+		t.Fatalf("Fingerprint mismatch. Expected %v, Found %v", graph.Fingerprint(), roundTrip.Fingerprint())
+	}
+
+	before := roundTrip.Fingerprint()
+	roundTrip.insert("stg_payments", "dim_customers")
+	if roundTrip.Fingerprint() == before {
+		t.Fatalf("Expected fingerprint to change after adding an edge")
+	}
+
+	// an isolated node changes the fingerprint
+	isolated := jaffleShopGraph()
+	isolated.getOrCreate("raw.events")
+	if isolated.Fingerprint() == graph.Fingerprint() {
+		t.Fatalf("Expected fingerprint to change after adding a node")
+	}
+}
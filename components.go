@@ -0,0 +1,50 @@
+package graph
+
+import "sort"
+
+// Returns the weakly connected components of the graph, i.e. the
+// groups of nodes connected by relations in either direction. Each
+// component is sorted and components are ordered by their first path.
+func (g *Graph) components() [][]string {
+	seen := make(map[string]bool, len(g.nodes))
+	components := [][]string{}
+	for _, path := range g.sortedPaths() {
+		if seen[path] {
+			continue
+		}
+		component := []string{}
+		queue := []string{path}
+		seen[path] = true
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			component = append(component, p)
+			node := g.nodes[p]
+			for _, neighbors := range [][]string{node.upstream, node.downstream} {
+				for _, n := range neighbors {
+					if !seen[n] {
+						seen[n] = true
+						queue = append(queue, n)
+					}
+				}
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+	return components
+}
+
+// ComponentColors maps every node to the index of its weakly connected
+// component, so that exporters can give each component its own color.
+// Components are numbered from 0 in the order of their first path,
+// which keeps the indices stable for the same graph.
+func (g *Graph) ComponentColors() map[string]int {
+	colors := make(map[string]int, len(g.nodes))
+	for i, component := range g.components() {
+		for _, path := range component {
+			colors[path] = i
+		}
+	}
+	return colors
+}
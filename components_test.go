@@ -0,0 +1,36 @@
+package graph
+
+import "testing"
+
+// TestComponentColors asserts that nodes of the same component share
+// an index and that the two components differ.
+func TestComponentColors(t *testing.T) {
+	graph := jaffleShopGraph()
+	graph.insert("hubspot.contacts", "stg_contacts")
+	graph.insert("stg_contacts", "marketing_report")
+
+	colors := graph.ComponentColors()
+	if len(colors) != 13 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 13, len(colors))
+	}
+	jaffle := colors["jaffle_shop.orders"]
+	for _, path := range []string{"stripe.payment", "gsheets.goals", "fct_orders", "weekly_jaffle_metrics"} {
+		if colors[path] != jaffle {
+			t.Fatalf("Color mismatch for %s. Expected %d, Found %d", path, jaffle, colors[path])
+		}
+	}
+	hubspot := colors["hubspot.contacts"]
+	for _, path := range []string{"stg_contacts", "marketing_report"} {
+		if colors[path] != hubspot {
+			t.Fatalf("Color mismatch for %s. Expected %d, Found %d", path, hubspot, colors[path])
+		}
+	}
+	if jaffle == hubspot {
+		t.Fatalf("Expected components to have different colors")
+	}
+
+	// components are numbered by their first path
+	if colors["dim_customers"] != 0 || hubspot != 1 {
+		t.Fatalf("Color order mismatch. Found %v", colors)
+	}
+}
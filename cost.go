@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+// InsertCost inserts the relation with the cost of following it.
+// Inserting the same pair again replaces the cost.
+func (g *Graph) InsertCost(from, to string, cost float64) {
+	g.insert(from, to)
+	g.edgeAttrs(from, to).Cost = cost
+}
+
+// Returns the cost of the edge, or 0 if it has no attributes.
+func (g *Graph) edgeCost(from, to string) float64 {
+	if attrs, ok := g.attrs[Edge{From: from, To: to}]; ok {
+		return attrs.Cost
+	}
+	return 0
+}
+
+// costItem is a node queued with the cheapest known cost to reach it.
+type costItem struct {
+	path string
+	cost float64
+}
+
+// costQueue is a min-heap of queued nodes ordered by cost, with ties
+// broken by path.
+type costQueue []costItem
+
+func (q costQueue) Len() int { return len(q) }
+func (q costQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	return q[i].path < q[j].path
+}
+func (q costQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *costQueue) Push(x interface{}) { *q = append(*q, x.(costItem)) }
+func (q *costQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// ReachableWithinCost returns the downstream nodes of the path whose
+// cheapest path from it costs at most the budget, summing the edge
+// costs set with InsertCost. Nodes are expanded cheapest first, as in
+// Dijkstra's algorithm, and the expansion stops at the first node over
+// the budget. The path itself is not included. The result is sorted.
+// Returns an error if an edge with a negative cost is reached.
+func (g *Graph) ReachableWithinCost(from string, budget float64) ([]string, error) {
+	if _, ok := g.nodes[from]; !ok {
+		return nil, &MissingNodeError{path: from}
+	}
+	settled := make(map[string]bool)
+	best := map[string]float64{from: 0}
+	queue := &costQueue{{path: from}}
+	result := []string{}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(costItem)
+		if settled[item.path] {
+			// skip stale entries of nodes reached more cheaply
+			continue
+		}
+		if item.cost > budget {
+			break
+		}
+		settled[item.path] = true
+		if item.path != from {
+			result = append(result, item.path)
+		}
+		for _, ds := range g.nodes[item.path].downstream {
+			edgeCost := g.edgeCost(item.path, ds)
+			if edgeCost < 0 {
+				return nil, fmt.Errorf("negative cost %v on edge from %s to %s", edgeCost, item.path, ds)
+			}
+			cost := item.cost + edgeCost
+			if c, ok := best[ds]; !settled[ds] && (!ok || cost < c) {
+				best[ds] = cost
+				heap.Push(queue, costItem{path: ds, cost: cost})
+			}
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
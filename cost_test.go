@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestReachableWithinCost builds a weighted chain with a cheaper detour
+// and checks that only the nodes within the budget are returned.
+func TestReachableWithinCost(t *testing.T) {
+	graph := &Graph{}
+	graph.InsertCost("jaffle_shop.orders", "stg_orders", 1)
+	graph.InsertCost("stg_orders", "fct_orders", 5)
+	graph.InsertCost("fct_orders", "weekly_jaffle_metrics", 2)
+	// the detour through int_orders reaches fct_orders for 3
+	graph.InsertCost("stg_orders", "int_orders", 1)
+	graph.InsertCost("int_orders", "fct_orders", 1)
+
+	tests := []struct {
+		budget   float64
+		expected string
+	}{
+		{0.5, ""},
+		{1, "stg_orders"},
+		{3, "fct_orders,int_orders,stg_orders"},
+		{4.5, "fct_orders,int_orders,stg_orders"},
+		{5, "fct_orders,int_orders,stg_orders,weekly_jaffle_metrics"},
+	}
+	for _, test := range tests {
+		reachable, err := graph.ReachableWithinCost("jaffle_shop.orders", test.budget)
+		if err != nil {
+			t.Fatalf("Error getting reachable nodes - %v", err)
+		}
+		if found := strings.Join(reachable, ","); found != test.expected {
+			t.Fatalf("Reachable mismatch for budget %v. Expected %v, Found %v", test.budget, test.expected, found)
+		}
+	}
+
+	if _, err := graph.ReachableWithinCost("missing", 1); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+	graph.InsertCost("weekly_jaffle_metrics", "finance_report", -1)
+	if _, err := graph.ReachableWithinCost("jaffle_shop.orders", 10); err == nil {
+		t.Fatalf("Expected error for negative cost")
+	}
+}
@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadCsv reads input CSV file row by row and creates a graph from
+// the given relationships. The first row is a header, and the source
+// and target are the first two columns unless the options name other
+// columns of the header. Leading and trailing whitespace is trimmed
+// from the source and target of every row, and rows where either is
+// empty after trimming are skipped and counted in the result.
+func LoadCsv(path string, opts LoadOptions) (*Graph, LoadResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, LoadResult{}, err
+	}
+	defer f.Close()
+	return loadCsv(f, opts)
+}
+
+// Creates a graph from the CSV rows read from r like LoadCsv.
+func loadCsv(r io.Reader, opts LoadOptions) (*Graph, LoadResult, error) {
+	result := LoadResult{}
+	csvReader := csv.NewReader(r)
+	csvReader.ReuseRecord = true
+	graph := &Graph{}
+	header, err := csvReader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return graph, result, nil
+		}
+		return nil, result, err
+	}
+	sourceCol, targetCol, err := opts.csvColumns(header)
+	if err != nil {
+		return nil, result, err
+	}
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, result, err
+		}
+		result.Rows++
+		source, target := strings.TrimSpace(record[sourceCol]), strings.TrimSpace(record[targetCol])
+		if source == "" || target == "" {
+			result.Skipped++
+			opts.logf("skipping row %d with empty source or target", result.Rows)
+		} else if err := opts.insert(graph, source, target, &result); err != nil {
+			return nil, result, err
+		}
+		opts.progress(result.Rows, false)
+	}
+	opts.progress(result.Rows, true)
+	return graph, result, nil
+}
+
+// Returns the indexes of the source and target columns in the CSV
+// header, looking up the column names set in the options. Returns an
+// error if a named column is missing or the header is too narrow for
+// a positional column.
+func (o *LoadOptions) csvColumns(header []string) (source, target int, err error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	lookup := func(name string, position int) (int, error) {
+		if name == "" {
+			if position >= len(header) {
+				return 0, fmt.Errorf("CSV header has %d columns", len(header))
+			}
+			return position, nil
+		}
+		i, ok := index[name]
+		if !ok {
+			return 0, fmt.Errorf("missing column %s in CSV header", name)
+		}
+		return i, nil
+	}
+	if source, err = lookup(o.SourceColName, 0); err != nil {
+		return 0, 0, err
+	}
+	if target, err = lookup(o.TargetColName, 1); err != nil {
+		return 0, 0, err
+	}
+	return source, target, nil
+}
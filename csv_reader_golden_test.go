@@ -0,0 +1,34 @@
+package graph_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	graph "grasskode/synq-graphs"
+	"grasskode/synq-graphs/graphtest"
+)
+
+// TestLoadCsvTrimsFields checks that fields are trimmed and that rows
+// with a whitespace-only field are skipped and counted.
+func TestLoadCsvTrimsFields(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "lineage.csv")
+	content := "source,target\n" +
+		"  , stg_orders\n" +
+		" jaffle_shop.orders , stg_orders\n" +
+		"stg_orders,fct_orders\n"
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatalf("Unable to write input file %s - %v", filename, err)
+	}
+
+	got, result, err := graph.LoadCsv(filename, graph.LoadOptions{})
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+	if result.Rows != 3 || result.Skipped != 1 {
+		t.Fatalf("Load result mismatch. Expected %v, Found %v", graph.LoadResult{Rows: 3, Skipped: 1}, result)
+	}
+
+	// no whitespace node is created and the trimmed node is kept
+	graphtest.AssertGraphEqual(t, got, graphtest.LoadFixture("orders_chain"))
+}
@@ -0,0 +1,167 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeCsv writes the given content to a CSV file in a temporary
+// directory and returns its path.
+func writeCsv(t *testing.T, content string) string {
+	filename := filepath.Join(t.TempDir(), "lineage.csv")
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatalf("Unable to write input file %s - %v", filename, err)
+	}
+	return filename
+}
+
+// TestLoadCsvProgress checks that the progress callback is invoked
+// while loading and ends with the number of data rows.
+func TestLoadCsvProgress(t *testing.T) {
+	filename := "synq-lineage.csv"
+	calls := []int{}
+	opts := LoadOptions{
+		OnProgress:       func(rowsRead int) { calls = append(calls, rowsRead) },
+		ProgressInterval: 100,
+	}
+	_, result, err := LoadCsv(filename, opts)
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+
+	// 300 data rows are reported every 100 rows without repeating
+	// the final count
+	expected := []int{100, 200, 300}
+	if len(calls) != len(expected) {
+		t.Fatalf("Progress calls mismatch. Expected %v, Found %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatalf("Progress calls mismatch. Expected %v, Found %v", expected, calls)
+		}
+	}
+	if result.Rows != 300 {
+		t.Fatalf("Row count mismatch. Expected %d, Found %d", 300, result.Rows)
+	}
+}
+
+// TestLoadCsvMaxNodes checks that loading stops with an error once the
+// node limit is exceeded.
+func TestLoadCsvMaxNodes(t *testing.T) {
+	filename := "synq-lineage.csv"
+	graph, result, err := LoadCsv(filename, LoadOptions{MaxNodes: 10})
+	var limitErr *NodeLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("Expected node limit error, Found %v", err)
+	}
+	if graph != nil {
+		t.Fatalf("Expected no graph when the limit is exceeded")
+	}
+	if result.Rows >= 300 {
+		t.Fatalf("Expected the load to stop early, Found %d rows read", result.Rows)
+	}
+	if !strings.Contains(err.Error(), "node limit of 10") {
+		t.Fatalf("Error mismatch. Found %v", err)
+	}
+
+	// the fixture loads within a sufficient limit
+	if _, _, err := LoadCsv(filename, LoadOptions{MaxNodes: 266}); err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+}
+
+// TestLoadCsvEdgeTransform checks that the edge transform rewrites the
+// paths of every edge and drops the edges it rejects.
+func TestLoadCsvEdgeTransform(t *testing.T) {
+	filename := writeCsv(t, "source,target\n"+
+		"Jaffle_Shop.Orders,STG_ORDERS\n"+
+		"stg_orders,marketing.campaigns\n"+
+		"stg_orders,fct_orders\n")
+
+	// lowercase all paths and drop edges into the marketing namespace
+	transform := func(from, to string) (string, string, bool) {
+		from, to = strings.ToLower(from), strings.ToLower(to)
+		return from, to, !strings.HasPrefix(to, "marketing.")
+	}
+	graph, result, err := LoadCsv(filename, LoadOptions{EdgeTransform: transform})
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+	if result.Rows != 3 || result.Dropped != 1 {
+		t.Fatalf("Load result mismatch. Expected %v, Found %v", LoadResult{Rows: 3, Dropped: 1}, result)
+	}
+	expected := "fct_orders,jaffle_shop.orders,stg_orders"
+	if nodes := strings.Join(graph.sortedPaths(), ","); nodes != expected {
+		t.Fatalf("Node mismatch. Expected %v, Found %v", expected, nodes)
+	}
+}
+
+// captureLogger records the formatted diagnostics it receives.
+type captureLogger struct {
+	lines []string
+}
+
+func (c *captureLogger) Printf(format string, v ...interface{}) {
+	c.lines = append(c.lines, fmt.Sprintf(format, v...))
+}
+
+// TestLoadCsvLogger checks that a skipped row is reported to the
+// injected logger.
+func TestLoadCsvLogger(t *testing.T) {
+	filename := writeCsv(t, "source,target\n"+
+		"stg_orders,fct_orders\n"+
+		"stg_payments,  \n")
+	logger := &captureLogger{}
+	_, _, err := LoadCsv(filename, LoadOptions{Logger: logger})
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+	expected := "skipping row 2 with empty source or target"
+	if len(logger.lines) != 1 || logger.lines[0] != expected {
+		t.Fatalf("Log mismatch. Expected %v, Found %v", []string{expected}, logger.lines)
+	}
+}
+
+// TestLoadCsvOrigin checks that the origin option is recorded on every
+// loaded edge.
+func TestLoadCsvOrigin(t *testing.T) {
+	filename := writeCsv(t, "source,target\nstg_orders,fct_orders\nstg_payments,fct_orders\n")
+	graph, _, err := LoadCsv(filename, LoadOptions{Origin: "dbt"})
+	if err != nil {
+		t.Fatalf("Unable to load %s - %v", filename, err)
+	}
+	if edges := graph.EdgesFromSource("dbt"); len(edges) != 2 {
+		t.Fatalf("Edge count mismatch. Expected %d, Found %d", 2, len(edges))
+	}
+}
+
+// TestLoadCsvNamedColumns loads a CSV whose header is target,extra,source
+// and checks that the named columns are picked regardless of position.
+func TestLoadCsvNamedColumns(t *testing.T) {
+	filename := writeCsv(t, "target,extra,source\nstg_orders,x,jaffle_shop.orders\nfct_orders,y,stg_orders\n")
+	graph, _, err := LoadCsv(filename, LoadOptions{SourceColName: "source", TargetColName: "target"})
+	if err != nil {
+		t.Fatalf("Unable to load %s - %v", filename, err)
+	}
+	expected := "jaffle_shop.orders->stg_orders,stg_orders->fct_orders"
+	if edges := edgeList(graph); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+
+	if _, _, err := LoadCsv(filename, LoadOptions{SourceColName: "upstream"}); err == nil {
+		t.Fatalf("Expected error for missing column")
+	}
+
+	// a single column header cannot hold a positional target
+	narrow := writeCsv(t, "source\nstg_orders\n")
+	if _, _, err := LoadCsv(narrow, LoadOptions{}); err == nil || err.Error() != "CSV header has 1 columns" {
+		t.Fatalf("Expected error for narrow header, Found %v", err)
+	}
+	if _, _, err := LoadCsv(narrow, LoadOptions{SourceColName: "source"}); err == nil {
+		t.Fatalf("Expected error for narrow header")
+	}
+}
@@ -0,0 +1,347 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+)
+
+// topologicalOrder returns the node paths ordered so that every node
+// appears after all of its upstream nodes, using Kahn's algorithm.
+// Nodes without pending upstreams are picked in path order so that
+// the order is stable. Returns a CycleError if the graph has a cycle.
+func (g *Graph) topologicalOrder() ([]string, error) {
+	pending := make(map[string]int, len(g.nodes))
+	ready := []string{}
+	for _, path := range g.sortedPaths() {
+		pending[path] = len(g.nodes[path].upstream)
+		if pending[path] == 0 {
+			ready = append(ready, path)
+		}
+	}
+
+	order := make([]string, 0, len(g.nodes))
+	for len(ready) > 0 {
+		path := ready[0]
+		ready = ready[1:]
+		order = append(order, path)
+		released := []string{}
+		for _, ds := range g.nodes[path].downstream {
+			pending[ds]--
+			if pending[ds] == 0 {
+				released = append(released, ds)
+			}
+		}
+		sort.Strings(released)
+		ready = append(ready, released...)
+	}
+
+	if len(order) != len(g.nodes) {
+		// any node left pending is on or behind a cycle
+		for _, path := range g.sortedPaths() {
+			if pending[path] > 0 {
+				return nil, &CycleError{path: path}
+			}
+		}
+	}
+	return order, nil
+}
+
+// TopologicalSort returns the node paths in dependency order, so that
+// every node appears after all of its upstream nodes. Ties are broken
+// by path, so the order is stable. Returns a CycleError if the graph
+// has a cycle.
+func (g *Graph) TopologicalSort() ([]string, error) {
+	return g.topologicalOrder()
+}
+
+// RedundantEdges returns the edges that a transitive reduction of the
+// graph would remove. An edge from A to C is redundant when C is also
+// reachable from A through another of A's downstream nodes. Edges are
+// sorted by source and target. Returns a CycleError if the graph has a
+// cycle since the reduction is not unique in that case.
+func (g *Graph) RedundantEdges() ([]Edge, error) {
+	if _, err := g.topologicalOrder(); err != nil {
+		return nil, err
+	}
+
+	redundant := []Edge{}
+	for _, path := range g.sortedPaths() {
+		node := g.nodes[path]
+		if len(node.downstream) < 2 {
+			continue
+		}
+		// nodes reachable in two or more hops from the path
+		indirect, err := g.downstream(node.downstream)
+		if err != nil {
+			return nil, err
+		}
+		reachable := make(map[string]bool, len(indirect))
+		for _, n := range indirect {
+			reachable[n] = true
+		}
+		downstream := append([]string{}, node.downstream...)
+		sort.Strings(downstream)
+		for _, ds := range downstream {
+			if reachable[ds] {
+				redundant = append(redundant, Edge{From: path, To: ds})
+			}
+		}
+	}
+	return redundant, nil
+}
+
+// Depth returns the length of the longest path from any root to the
+// given path. Roots have a depth of 0. Returns a CycleError if the
+// path has a cycle upstream.
+func (g *Graph) Depth(path string) (int, error) {
+	return g.longestPath(path, upstreamOf)
+}
+
+// Height returns the length of the longest path from the given path
+// to any leaf. Leaves have a height of 0. Returns a CycleError if the
+// path has a cycle downstream.
+func (g *Graph) Height(path string) (int, error) {
+	return g.longestPath(path, downstreamOf)
+}
+
+// Returns the length of the longest path from the given path along
+// the relations selected by next. Returns a CycleError if a cycle is
+// reachable from the path.
+func (g *Graph) longestPath(path string, next func(*Node) []string) (int, error) {
+	if _, ok := g.nodes[path]; !ok {
+		return 0, &MissingNodeError{path: path}
+	}
+
+	lengths := make(map[string]int)
+	visiting := make(map[string]bool)
+	var longest func(p string) (int, error)
+	longest = func(p string) (int, error) {
+		if l, ok := lengths[p]; ok {
+			return l, nil
+		}
+		if visiting[p] {
+			return 0, &CycleError{path: p}
+		}
+		visiting[p] = true
+		l := 0
+		for _, n := range next(g.nodes[p]) {
+			d, err := longest(n)
+			if err != nil {
+				return 0, err
+			}
+			if d+1 > l {
+				l = d + 1
+			}
+		}
+		visiting[p] = false
+		lengths[p] = l
+		return l, nil
+	}
+	return longest(path)
+}
+
+// ErrNoCommonAncestor is returned by LowestCommonAncestor when the two
+// nodes share no upstream lineage.
+var ErrNoCommonAncestor = errors.New("no common ancestor")
+
+// LowestCommonAncestor returns the shared ancestor of the two nodes
+// that is furthest from the roots, i.e. the one with the greatest
+// depth. A node counts as its own ancestor, so if one node is upstream
+// of the other it is returned. Ties are broken by path order. Returns
+// ErrNoCommonAncestor if the nodes share no ancestor.
+func (g *Graph) LowestCommonAncestor(a, b string) (string, error) {
+	ancestorsOf := func(path string) (map[string]bool, error) {
+		upstream, err := g.upstream([]string{path})
+		if err != nil {
+			return nil, err
+		}
+		ancestors := map[string]bool{path: true}
+		for _, up := range upstream {
+			ancestors[up] = true
+		}
+		return ancestors, nil
+	}
+	ancestorsA, err := ancestorsOf(a)
+	if err != nil {
+		return "", err
+	}
+	ancestorsB, err := ancestorsOf(b)
+	if err != nil {
+		return "", err
+	}
+
+	lowest, lowestDepth := "", -1
+	for path := range ancestorsA {
+		if !ancestorsB[path] {
+			continue
+		}
+		depth, err := g.Depth(path)
+		if err != nil {
+			return "", err
+		}
+		if depth > lowestDepth || (depth == lowestDepth && path < lowest) {
+			lowest, lowestDepth = path, depth
+		}
+	}
+	if lowestDepth < 0 {
+		return "", ErrNoCommonAncestor
+	}
+	return lowest, nil
+}
+
+// FindCycle returns one cycle in the graph and true, or nil and false
+// if the graph is acyclic. The cycle is returned in lineage order, so
+// each path has an edge to the next and the last has an edge back to
+// the first. The depth first search stops at the first back edge.
+func (g *Graph) FindCycle() ([]string, bool) {
+	visited := make(map[string]bool, len(g.nodes))
+	onStack := make(map[string]int)
+	stack := []string{}
+	var visit func(path string) []string
+	visit = func(path string) []string {
+		visited[path] = true
+		onStack[path] = len(stack)
+		stack = append(stack, path)
+		for _, ds := range g.nodes[path].downstream {
+			if i, ok := onStack[ds]; ok {
+				// back edge, the cycle is the stack from ds onwards
+				return append([]string{}, stack[i:]...)
+			}
+			if !visited[ds] {
+				if cycle := visit(ds); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		delete(onStack, path)
+		return nil
+	}
+
+	for _, path := range g.sortedPaths() {
+		if visited[path] {
+			continue
+		}
+		if cycle := visit(path); cycle != nil {
+			return cycle, true
+		}
+	}
+	return nil, false
+}
+
+// HasCycle reports whether the graph has a cycle.
+func (g *Graph) HasCycle() bool {
+	_, ok := g.FindCycle()
+	return ok
+}
+
+// FindCycles returns the nodes participating in cycles, grouped by the
+// strongly connected components of the graph found with Tarjan's
+// algorithm. Every component with more than one node, or a single node
+// with an edge to itself, is reported, so every node on a cycle is in
+// exactly one group. The paths of each group are sorted and the groups
+// are ordered by their first path. Returns an empty slice if the graph
+// is acyclic.
+func (g *Graph) FindCycles() [][]string {
+	index := make(map[string]int, len(g.nodes))
+	lowlink := make(map[string]int, len(g.nodes))
+	onStack := make(map[string]bool)
+	stack := []string{}
+	cycles := [][]string{}
+	var visit func(path string)
+	visit = func(path string) {
+		index[path] = len(index)
+		lowlink[path] = index[path]
+		onStack[path] = true
+		stack = append(stack, path)
+		for _, ds := range g.nodes[path].downstream {
+			if _, ok := index[ds]; !ok {
+				visit(ds)
+				if lowlink[ds] < lowlink[path] {
+					lowlink[path] = lowlink[ds]
+				}
+			} else if onStack[ds] && index[ds] < lowlink[path] {
+				lowlink[path] = index[ds]
+			}
+		}
+		if lowlink[path] != index[path] {
+			return
+		}
+		// path is the root of a component, pop it off the stack
+		component := []string{}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == path {
+				break
+			}
+		}
+		if len(component) > 1 || g.hasEdge(path, path) {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, path := range g.sortedPaths() {
+		if _, ok := index[path]; !ok {
+			visit(path)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// Returns the product of two path counts, saturating at maxPathCount.
+func saturatingProduct(a, b int) int {
+	if a != 0 && b > maxPathCount/a {
+		return maxPathCount
+	}
+	return a * b
+}
+
+// EdgeTraversalFrequency counts, over all the paths from a root to a
+// leaf, how many paths include each edge. Edges on every path share
+// the highest count. Counts saturate at maxPathCount so that densely
+// connected graphs do not overflow. Returns a CycleError if the graph
+// has a cycle.
+func (g *Graph) EdgeTraversalFrequency() (map[[2]string]int, error) {
+	order, err := g.topologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	// paths from any root to each node, and from each node to any leaf
+	fromRoots := make(map[string]int, len(order))
+	for _, path := range order {
+		node := g.nodes[path]
+		if len(node.upstream) == 0 {
+			fromRoots[path] = 1
+		}
+		for _, ds := range node.downstream {
+			fromRoots[ds] += fromRoots[path]
+			if fromRoots[ds] > maxPathCount {
+				fromRoots[ds] = maxPathCount
+			}
+		}
+	}
+	toLeaves := make(map[string]int, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		node := g.nodes[order[i]]
+		if len(node.downstream) == 0 {
+			toLeaves[order[i]] = 1
+		}
+		for _, up := range node.upstream {
+			toLeaves[up] += toLeaves[order[i]]
+			if toLeaves[up] > maxPathCount {
+				toLeaves[up] = maxPathCount
+			}
+		}
+	}
+
+	frequency := make(map[[2]string]int)
+	for _, edge := range g.edges() {
+		frequency[[2]string{edge.From, edge.To}] = saturatingProduct(fromRoots[edge.From], toLeaves[edge.To])
+	}
+	return frequency, nil
+}
@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRedundantEdges asserts that an explicit shortcut edge is
+// reported as redundant.
+func TestRedundantEdges(t *testing.T) {
+	graph := &Graph{}
+	graph.insert("a", "b")
+	graph.insert("b", "c")
+	graph.insert("a", "c")
+	graph.insert("c", "d")
+
+	redundant, err := graph.RedundantEdges()
+	if err != nil {
+		t.Fatalf("Error getting redundant edges - %v", err)
+	}
+	if len(redundant) != 1 || redundant[0] != (Edge{From: "a", To: "c"}) {
+		t.Fatalf("Redundant edges mismatch. Expected %v, Found %v", []Edge{{From: "a", To: "c"}}, redundant)
+	}
+
+	// the jaffle_shop graph has no shortcuts
+	redundant, err = jaffleShopGraph().RedundantEdges()
+	if err != nil {
+		t.Fatalf("Error getting redundant edges - %v", err)
+	}
+	if len(redundant) != 0 {
+		t.Fatalf("Redundant edges mismatch. Expected %v, Found %v", []Edge{}, redundant)
+	}
+
+	// a cycle is an error
+	graph.insert("d", "a")
+	var cycleErr *CycleError
+	if _, err := graph.RedundantEdges(); !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected cycle error, Found %v", err)
+	}
+}
+
+// TestDepthAndHeight asserts the depth and height of nodes in the
+// jaffle_shop graph.
+func TestDepthAndHeight(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	expected := map[string][2]int{
+		"jaffle_shop.orders":    {0, 3},
+		"gsheets.goals":         {0, 1},
+		"fct_orders":            {2, 1},
+		"weekly_jaffle_metrics": {3, 0},
+	}
+	for path, want := range expected {
+		depth, err := graph.Depth(path)
+		if err != nil {
+			t.Fatalf("Error getting depth - %v", err)
+		}
+		height, err := graph.Height(path)
+		if err != nil {
+			t.Fatalf("Error getting height - %v", err)
+		}
+		if depth != want[0] || height != want[1] {
+			t.Fatalf("Depth and height mismatch for %s. Expected %v, Found %v", path, want, [2]int{depth, height})
+		}
+	}
+
+	if _, err := graph.Depth("missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+
+	// a cycle is an error
+	graph.insert("weekly_jaffle_metrics", "stg_orders")
+	var cycleErr *CycleError
+	if _, err := graph.Height("fct_orders"); !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected cycle error, Found %v", err)
+	}
+}
+
+// TestLowestCommonAncestor asserts that the closest shared ancestor is
+// returned rather than a more distant shared source.
+func TestLowestCommonAncestor(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	lca, err := graph.LowestCommonAncestor("dim_customers", "fct_orders")
+	if err != nil {
+		t.Fatalf("Error getting common ancestor - %v", err)
+	}
+	if lca != "stg_orders" {
+		t.Fatalf("Common ancestor mismatch. Expected %v, Found %v", "stg_orders", lca)
+	}
+
+	// a node is its own ancestor
+	lca, err = graph.LowestCommonAncestor("stg_orders", "weekly_jaffle_metrics")
+	if err != nil {
+		t.Fatalf("Error getting common ancestor - %v", err)
+	}
+	if lca != "stg_orders" {
+		t.Fatalf("Common ancestor mismatch. Expected %v, Found %v", "stg_orders", lca)
+	}
+
+	if _, err := graph.LowestCommonAncestor("dim_customers", "stripe.payment"); !errors.Is(err, ErrNoCommonAncestor) {
+		t.Fatalf("Expected no common ancestor, Found %v", err)
+	}
+	if _, err := graph.LowestCommonAncestor("dim_customers", "missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestFindCycle asserts that a valid cycle is returned for a cyclic
+// graph and none for a DAG.
+func TestFindCycle(t *testing.T) {
+	graph := jaffleShopGraph()
+	if cycle, ok := graph.FindCycle(); ok {
+		t.Fatalf("Expected no cycle, Found %v", cycle)
+	}
+
+	graph.insert("weekly_jaffle_metrics", "stg_orders")
+	cycle, ok := graph.FindCycle()
+	if !ok {
+		t.Fatalf("Expected a cycle")
+	}
+	if len(cycle) < 2 {
+		t.Fatalf("Cycle too short. Found %v", cycle)
+	}
+	// every path has an edge to the next, wrapping around
+	for i, path := range cycle {
+		next := cycle[(i+1)%len(cycle)]
+		if !graph.hasEdge(path, next) {
+			t.Fatalf("Invalid cycle %v, missing edge %s -> %s", cycle, path, next)
+		}
+	}
+	if !contains(cycle, "stg_orders") || !contains(cycle, "weekly_jaffle_metrics") {
+		t.Fatalf("Cycle mismatch. Found %v", cycle)
+	}
+}
+
+// TestTopologicalSort checks that every node of the jaffle_shop graph
+// comes after its upstream nodes and that a cycle is an error.
+func TestTopologicalSort(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	order, err := graph.TopologicalSort()
+	if err != nil {
+		t.Fatalf("Error sorting graph - %v", err)
+	}
+	if len(order) != 10 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 10, len(order))
+	}
+	position := make(map[string]int, len(order))
+	for i, path := range order {
+		position[path] = i
+	}
+	for _, edge := range graph.edges() {
+		if position[edge.From] > position[edge.To] {
+			t.Fatalf("Order mismatch. Expected %s before %s in %v", edge.From, edge.To, order)
+		}
+	}
+
+	graph.insert("weekly_jaffle_metrics", "stg_orders")
+	var cycleErr *CycleError
+	if _, err := graph.TopologicalSort(); !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected CycleError, Found %v", err)
+	}
+}
+
+// TestFindCycles adds two separate cycles to the jaffle_shop graph
+// and checks that both are found.
+func TestFindCycles(t *testing.T) {
+	graph := jaffleShopGraph()
+	if graph.HasCycle() {
+		t.Fatalf("Expected no cycle")
+	}
+	if cycles := graph.FindCycles(); len(cycles) != 0 {
+		t.Fatalf("Expected no cycles, Found %v", cycles)
+	}
+
+	graph.insert("stg_customers", "jaffle_shop.customers")
+	graph.insert("weekly_jaffle_metrics", "fct_orders")
+	graph.insert("gsheets.goals", "gsheets.goals")
+	if !graph.HasCycle() {
+		t.Fatalf("Expected a cycle")
+	}
+	format := func(cycles [][]string) string {
+		groups := []string{}
+		for _, cycle := range cycles {
+			groups = append(groups, strings.Join(cycle, ","))
+		}
+		return strings.Join(groups, "|")
+	}
+	expected := "fct_orders,weekly_jaffle_metrics|gsheets.goals|jaffle_shop.customers,stg_customers"
+	if found := format(graph.FindCycles()); found != expected {
+		t.Fatalf("Cycle mismatch. Expected %v, Found %v", expected, found)
+	}
+
+	// d is only on the cycle a->d->b->c->a through a cross edge
+	graph = &Graph{}
+	graph.insert("a", "b")
+	graph.insert("b", "c")
+	graph.insert("c", "a")
+	graph.insert("a", "d")
+	graph.insert("d", "b")
+	graph.insert("c", "e")
+	if found := format(graph.FindCycles()); found != "a,b,c,d" {
+		t.Fatalf("Cycle mismatch. Expected %v, Found %v", "a,b,c,d", found)
+	}
+}
+
+// TestEdgeTraversalFrequency asserts that an edge on every root to
+// leaf path has the highest frequency.
+func TestEdgeTraversalFrequency(t *testing.T) {
+	graph := &Graph{}
+	graph.insert("jaffle_shop.orders", "stg_orders")
+	graph.insert("stripe.payment", "stg_payments")
+	graph.insert("stg_orders", "fct_orders")
+	graph.insert("stg_payments", "fct_orders")
+	graph.insert("fct_orders", "orders_mart")
+	graph.insert("orders_mart", "finance_report")
+	graph.insert("orders_mart", "exec_dashboard")
+
+	frequency, err := graph.EdgeTraversalFrequency()
+	if err != nil {
+		t.Fatalf("Error getting edge frequency - %v", err)
+	}
+	// 2 roots times 2 leaves give 4 paths, all through fct_orders
+	expected := map[[2]string]int{
+		{"jaffle_shop.orders", "stg_orders"}: 2,
+		{"stripe.payment", "stg_payments"}:   2,
+		{"stg_orders", "fct_orders"}:         2,
+		{"stg_payments", "fct_orders"}:       2,
+		{"fct_orders", "orders_mart"}:        4,
+		{"orders_mart", "finance_report"}:    2,
+		{"orders_mart", "exec_dashboard"}:    2,
+	}
+	if len(frequency) != len(expected) {
+		t.Fatalf("Frequency mismatch. Expected %v, Found %v", expected, frequency)
+	}
+	for edge, count := range expected {
+		if frequency[edge] != count {
+			t.Fatalf("Frequency mismatch for %v. Expected %d, Found %d", edge, count, frequency[edge])
+		}
+	}
+
+	graph.insert("finance_report", "jaffle_shop.orders")
+	if _, err := graph.EdgeTraversalFrequency(); err == nil {
+		t.Fatalf("Expected error for cyclic graph")
+	}
+}
@@ -0,0 +1,241 @@
+package graph
+
+import "sort"
+
+// GraphDiff holds the differences from an old graph to a new graph.
+// All slices are sorted.
+type GraphDiff struct {
+	AddedNodes   []string
+	RemovedNodes []string
+	AddedEdges   []Edge
+	RemovedEdges []Edge
+	// Renames is only set by DiffWithRenames.
+	Renames []Rename
+}
+
+// Rename reports a node of the old graph that was renamed in the new
+// graph.
+type Rename struct {
+	From string
+	To   string
+}
+
+// Empty reports whether the graphs have no differences.
+func (d GraphDiff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 && len(d.Renames) == 0
+}
+
+// Diff returns the nodes and edges added and removed from the old
+// graph to the new graph.
+func Diff(old, new *Graph) GraphDiff {
+	diff := GraphDiff{
+		AddedNodes:   []string{},
+		RemovedNodes: []string{},
+		AddedEdges:   []Edge{},
+		RemovedEdges: []Edge{},
+		Renames:      []Rename{},
+	}
+	for _, path := range old.sortedPaths() {
+		if _, ok := new.nodes[path]; !ok {
+			diff.RemovedNodes = append(diff.RemovedNodes, path)
+		}
+	}
+	for _, path := range new.sortedPaths() {
+		if _, ok := old.nodes[path]; !ok {
+			diff.AddedNodes = append(diff.AddedNodes, path)
+		}
+	}
+	for _, edge := range old.edges() {
+		if !new.hasEdge(edge.From, edge.To) {
+			diff.RemovedEdges = append(diff.RemovedEdges, edge)
+		}
+	}
+	for _, edge := range new.edges() {
+		if !old.hasEdge(edge.From, edge.To) {
+			diff.AddedEdges = append(diff.AddedEdges, edge)
+		}
+	}
+	return diff
+}
+
+// Returns the neighbor set of the node, with upstream and downstream
+// relations kept apart.
+func (g *Graph) neighborSet(path string) map[string]bool {
+	node := g.nodes[path]
+	set := make(map[string]bool, len(node.upstream)+len(node.downstream))
+	for _, up := range node.upstream {
+		set["up:"+up] = true
+	}
+	for _, ds := range node.downstream {
+		set["down:"+ds] = true
+	}
+	return set
+}
+
+// Returns the Jaccard similarity of two neighbor sets, or 0 if both
+// are empty.
+func similarity(a, b map[string]bool) float64 {
+	shared := 0
+	for n := range a {
+		if b[n] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// DiffWithRenames returns the differences like Diff, but reports a
+// removed node and an added node as a rename when the similarity of
+// their neighbor sets is at least minSimilarity, a value in (0, 1]
+// where 1 requires identical neighbors. Renamed nodes are dropped from
+// the added and removed nodes, and so are the edges that only changed
+// because of the rename. Candidates are matched greedily from the most
+// similar pair, ties broken by path, and isolated nodes are never
+// matched.
+func DiffWithRenames(old, new *Graph, minSimilarity float64) GraphDiff {
+	diff := Diff(old, new)
+	type candidate struct {
+		rename     Rename
+		similarity float64
+	}
+	candidates := []candidate{}
+	for _, from := range diff.RemovedNodes {
+		neighbors := old.neighborSet(from)
+		for _, to := range diff.AddedNodes {
+			if s := similarity(neighbors, new.neighborSet(to)); s > 0 && s >= minSimilarity {
+				candidates = append(candidates, candidate{Rename{From: from, To: to}, s})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+
+	renamed := make(map[string]string)
+	taken := make(map[string]bool)
+	for _, c := range candidates {
+		if _, ok := renamed[c.rename.From]; ok || taken[c.rename.To] {
+			continue
+		}
+		renamed[c.rename.From] = c.rename.To
+		taken[c.rename.To] = true
+		diff.Renames = append(diff.Renames, c.rename)
+	}
+	if len(diff.Renames) == 0 {
+		return diff
+	}
+	sort.Slice(diff.Renames, func(i, j int) bool { return diff.Renames[i].From < diff.Renames[j].From })
+
+	rename := func(path string) string {
+		if to, ok := renamed[path]; ok {
+			return to
+		}
+		return path
+	}
+	// a removed edge that exists in the new graph once renamed, and
+	// the added edge it became, are not changes
+	moved := make(map[Edge]bool)
+	removedEdges := []Edge{}
+	for _, edge := range diff.RemovedEdges {
+		translated := Edge{From: rename(edge.From), To: rename(edge.To)}
+		if new.hasEdge(translated.From, translated.To) {
+			moved[translated] = true
+			continue
+		}
+		removedEdges = append(removedEdges, edge)
+	}
+	addedEdges := []Edge{}
+	for _, edge := range diff.AddedEdges {
+		if !moved[edge] {
+			addedEdges = append(addedEdges, edge)
+		}
+	}
+	diff.RemovedEdges, diff.AddedEdges = removedEdges, addedEdges
+
+	removedNodes := []string{}
+	for _, path := range diff.RemovedNodes {
+		if _, ok := renamed[path]; !ok {
+			removedNodes = append(removedNodes, path)
+		}
+	}
+	addedNodes := []string{}
+	for _, path := range diff.AddedNodes {
+		if !taken[path] {
+			addedNodes = append(addedNodes, path)
+		}
+	}
+	diff.RemovedNodes, diff.AddedNodes = removedNodes, addedNodes
+	return diff
+}
+
+// DownstreamDiff returns the downstream nodes of the path that are
+// only in the new graph as added, and those only in the old graph as
+// removed, both sorted. A path missing from one graph has no
+// downstream there. Returns a MissingNodeError if the path is in
+// neither graph.
+func DownstreamDiff(old, new *Graph, path string) (added, removed []string, err error) {
+	_, inOld := old.nodes[path]
+	_, inNew := new.nodes[path]
+	if !inOld && !inNew {
+		return nil, nil, &MissingNodeError{path: path}
+	}
+	downstreamOfPath := func(g *Graph, ok bool) (map[string]bool, error) {
+		set := make(map[string]bool)
+		if !ok {
+			return set, nil
+		}
+		downstream, err := g.downstream([]string{path})
+		for _, ds := range downstream {
+			set[ds] = true
+		}
+		return set, err
+	}
+	before, err := downstreamOfPath(old, inOld)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := downstreamOfPath(new, inNew)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	added, removed = []string{}, []string{}
+	for ds := range after {
+		if !before[ds] {
+			added = append(added, ds)
+		}
+	}
+	for ds := range before {
+		if !after[ds] {
+			removed = append(removed, ds)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed, nil
+}
+
+// Intersect returns a new graph with the nodes present in both graphs
+// and the edges present in both graphs. Edge attributes are taken
+// from the receiver.
+func (g *Graph) Intersect(other *Graph) *Graph {
+	graph := &Graph{}
+	for _, path := range g.sortedPaths() {
+		if _, ok := other.nodes[path]; !ok {
+			continue
+		}
+		graph.getOrCreate(path)
+		for _, ds := range g.nodes[path].downstream {
+			if other.hasEdge(path, ds) {
+				graph.insert(path, ds)
+				graph.copyAttrs(g, Edge{From: path, To: ds})
+			}
+		}
+	}
+	return graph
+}
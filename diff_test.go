@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDiff asserts the added and removed nodes and edges between two
+// versions of the jaffle_shop graph.
+func TestDiff(t *testing.T) {
+	old := jaffleShopGraph()
+	new := jaffleShopGraph()
+	new.removeNode("gsheets.goals")
+	new.insert("stg_payments", "fct_payments")
+
+	diff := Diff(old, new)
+	if strings.Join(diff.AddedNodes, ",") != "fct_payments" {
+		t.Fatalf("Added nodes mismatch. Expected %v, Found %v", []string{"fct_payments"}, diff.AddedNodes)
+	}
+	if strings.Join(diff.RemovedNodes, ",") != "gsheets.goals" {
+		t.Fatalf("Removed nodes mismatch. Expected %v, Found %v", []string{"gsheets.goals"}, diff.RemovedNodes)
+	}
+	added := []Edge{{From: "stg_payments", To: "fct_payments"}}
+	if len(diff.AddedEdges) != 1 || diff.AddedEdges[0] != added[0] {
+		t.Fatalf("Added edges mismatch. Expected %v, Found %v", added, diff.AddedEdges)
+	}
+	removed := []Edge{{From: "gsheets.goals", To: "weekly_jaffle_metrics"}}
+	if len(diff.RemovedEdges) != 1 || diff.RemovedEdges[0] != removed[0] {
+		t.Fatalf("Removed edges mismatch. Expected %v, Found %v", removed, diff.RemovedEdges)
+	}
+
+	if !Diff(old, jaffleShopGraph()).Empty() {
+		t.Fatalf("Expected no differences")
+	}
+}
+
+// TestDiffWithRenames renames a node between two graphs and asserts
+// that the diff reports a rename when its neighbors are unchanged.
+func TestDiffWithRenames(t *testing.T) {
+	old := jaffleShopGraph()
+	new := jaffleShopGraph()
+	new.removeNode("fct_orders")
+	new.insert("stg_orders", "orders")
+	new.insert("stg_payments", "orders")
+	new.insert("orders", "weekly_jaffle_metrics")
+	new.insert("stg_payments", "fct_payments")
+
+	diff := DiffWithRenames(old, new, 1)
+	expected := []Rename{{From: "fct_orders", To: "orders"}}
+	if len(diff.Renames) != 1 || diff.Renames[0] != expected[0] {
+		t.Fatalf("Renames mismatch. Expected %v, Found %v", expected, diff.Renames)
+	}
+	if len(diff.RemovedNodes) != 0 || strings.Join(diff.AddedNodes, ",") != "fct_payments" {
+		t.Fatalf("Nodes mismatch. Expected [] and [fct_payments], Found %v and %v", diff.RemovedNodes, diff.AddedNodes)
+	}
+	added := []Edge{{From: "stg_payments", To: "fct_payments"}}
+	if len(diff.RemovedEdges) != 0 || len(diff.AddedEdges) != 1 || diff.AddedEdges[0] != added[0] {
+		t.Fatalf("Edges mismatch. Expected [] and %v, Found %v and %v", added, diff.RemovedEdges, diff.AddedEdges)
+	}
+
+	// with a changed neighbor set the node is no longer an exact match
+	new.removeEdge("stg_payments", "orders")
+	diff = DiffWithRenames(old, new, 1)
+	if len(diff.Renames) != 0 || strings.Join(diff.RemovedNodes, ",") != "fct_orders" {
+		t.Fatalf("Expected fct_orders to be removed, Found renames %v and removed nodes %v", diff.Renames, diff.RemovedNodes)
+	}
+	if diff = DiffWithRenames(old, new, 0.5); len(diff.Renames) != 1 {
+		t.Fatalf("Renames mismatch. Expected %v, Found %v", expected, diff.Renames)
+	}
+}
+
+// TestDownstreamDiff adds a downstream edge in a new version and
+// asserts the added and removed downstream of stg_payments.
+func TestDownstreamDiff(t *testing.T) {
+	old := jaffleShopGraph()
+	new := jaffleShopGraph()
+	new.insert("stg_payments", "fct_payments")
+	new.insert("fct_payments", "finance_report")
+
+	added, removed, err := DownstreamDiff(old, new, "stg_payments")
+	if err != nil {
+		t.Fatalf("Error getting downstream diff - %v", err)
+	}
+	if result := strings.Join(added, ","); result != "fct_payments,finance_report" {
+		t.Fatalf("Added mismatch. Expected %v, Found %v", "fct_payments,finance_report", result)
+	}
+	if len(removed) != 0 {
+		t.Fatalf("Removed mismatch. Expected %v, Found %v", []string{}, removed)
+	}
+
+	// reversing the versions reports the nodes as removed
+	added, removed, err = DownstreamDiff(new, old, "stg_payments")
+	if err != nil || len(added) != 0 || len(removed) != 2 {
+		t.Fatalf("Expected 2 removed nodes, Found %v and %v and %v", added, removed, err)
+	}
+
+	if _, _, err := DownstreamDiff(old, new, "missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestWriteDiffDOT asserts the colors of the added and removed edges
+// in the DOT output.
+func TestWriteDiffDOT(t *testing.T) {
+	old := jaffleShopGraph()
+	new := jaffleShopGraph()
+	new.removeEdge("stg_orders", "dim_customers")
+	new.insert("stg_payments", "dim_customers")
+
+	var out strings.Builder
+	if err := WriteDiffDOT(&out, old, new); err != nil {
+		t.Fatalf("Error writing diff - %v", err)
+	}
+	dot := out.String()
+
+	expected := []string{
+		`"stg_payments" -> "dim_customers" [color=green];`,
+		`"stg_orders" -> "dim_customers" [color=red, style=dashed];`,
+		`"dim_customers";`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(dot, line) {
+			t.Fatalf("Expected line %s in output\n%s", line, dot)
+		}
+	}
+	if !strings.HasPrefix(dot, "digraph {") || strings.Contains(dot, "fct_orders") {
+		t.Fatalf("Expected only the changed lineage in output\n%s", dot)
+	}
+}
+
+// TestIntersect asserts that the intersection of two overlapping
+// graphs holds exactly the shared nodes and edges.
+func TestIntersect(t *testing.T) {
+	prod := jaffleShopGraph()
+	prod.insert("stg_payments", "fct_payments")
+
+	dev := &Graph{}
+	dev.insert("stg_orders", "fct_orders")
+	dev.insert("stg_payments", "fct_orders")
+	dev.insert("stg_payments", "dim_customers")
+	dev.insert("fct_orders", "dev_report")
+
+	shared := prod.Intersect(dev)
+	nodes := strings.Join(shared.sortedPaths(), ",")
+	expectedNodes := "dim_customers,fct_orders,stg_orders,stg_payments"
+	if nodes != expectedNodes {
+		t.Fatalf("Node mismatch. Expected %v, Found %v", expectedNodes, nodes)
+	}
+	edges := edgeList(shared)
+	expectedEdges := "stg_orders->fct_orders,stg_payments->fct_orders"
+	if edges != expectedEdges {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expectedEdges, edges)
+	}
+
+	if !dev.Intersect(prod).Equal(shared) {
+		t.Fatalf("Expected intersection to be symmetric")
+	}
+}
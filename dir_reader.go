@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// NewGraphFromDir walks the directory and creates a graph from the
+// edges that parse returns for every regular file in it. parse is
+// called with the path of the file and its content, in lexical path
+// order, and the edges of all files are merged into one graph.
+func NewGraphFromDir(dir string, parse func(path string, content []byte) []Edge) (*Graph, error) {
+	graph, _, err := LoadDir(dir, parse, LoadOptions{})
+	return graph, err
+}
+
+// LoadDir walks the directory like NewGraphFromDir, applying the load
+// options to every parsed edge. Each edge counts as a row.
+func LoadDir(dir string, parse func(path string, content []byte) []Edge, opts LoadOptions) (*Graph, LoadResult, error) {
+	result := LoadResult{}
+	graph := &Graph{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, edge := range parse(path, content) {
+			result.Rows++
+			if err := opts.insert(graph, edge.From, edge.To, &result); err != nil {
+				return err
+			}
+			opts.progress(result.Rows, false)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, result, err
+	}
+	opts.progress(result.Rows, true)
+	return graph, result, nil
+}
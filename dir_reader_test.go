@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGraphFromDir reads a directory with one file per model listing
+// its dependencies and checks the merged graph.
+func TestGraphFromDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"fct_orders.deps":    "stg_orders\nstg_payments\n",
+		"dim_customers.deps": "stg_customers\nstg_orders\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Unable to write input file %s - %v", name, err)
+		}
+	}
+
+	// each file is named after the model and lists one upstream per line
+	parse := func(path string, content []byte) []Edge {
+		model := strings.TrimSuffix(filepath.Base(path), ".deps")
+		edges := []Edge{}
+		for _, dep := range strings.Fields(string(content)) {
+			edges = append(edges, Edge{From: dep, To: model})
+		}
+		return edges
+	}
+	graph, err := NewGraphFromDir(dir, parse)
+	if err != nil {
+		t.Fatalf("Unable to read input directory %s - %v", dir, err)
+	}
+
+	edges := edgeList(graph)
+	expected := "stg_customers->dim_customers,stg_orders->dim_customers,stg_orders->fct_orders,stg_payments->fct_orders"
+	if edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+
+	// the load options are applied to every parsed edge
+	drop := func(from, to string) (string, string, bool) { return from, to, to != "dim_customers" }
+	graph, result, err := LoadDir(dir, parse, LoadOptions{EdgeTransform: drop})
+	if err != nil {
+		t.Fatalf("Unable to read input directory %s - %v", dir, err)
+	}
+	expected = "stg_orders->fct_orders,stg_payments->fct_orders"
+	if edges := edgeList(graph); edges != expected || result.Rows != 4 || result.Dropped != 2 {
+		t.Fatalf("Edge mismatch. Expected %v with 2 dropped, Found %v with %v", expected, edges, result)
+	}
+
+	if _, err := NewGraphFromDir(filepath.Join(dir, "missing"), parse); err == nil {
+		t.Fatalf("Expected error for missing directory")
+	}
+}
@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrEngineClosed is returned by a QueryEngine that has been closed.
+var ErrEngineClosed = errors.New("query engine closed")
+
+// QueryEngine answers lineage queries over a static graph using a
+// fixed pool of workers shared by all callers, so that concurrent
+// requests never run more than the configured number of traversals at
+// once. The graph must not be modified while the engine is in use.
+type QueryEngine struct {
+	jobs   chan queryJob
+	closed chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+	// query runs a single traversal. Replaced in tests.
+	query func(paths []string) ([]string, error)
+}
+
+// queryJob is a single request queued for the workers.
+type queryJob struct {
+	ctx    context.Context
+	paths  []string
+	result chan queryResult
+}
+
+// queryResult holds the outcome of a queryJob.
+type queryResult struct {
+	paths []string
+	err   error
+}
+
+// NewQueryEngine starts a query engine over the graph with the given
+// number of workers. At least one worker is started. Close must be
+// called to stop the workers.
+func NewQueryEngine(graph *Graph, workers int) *QueryEngine {
+	if workers < 1 {
+		workers = 1
+	}
+	e := &QueryEngine{
+		jobs:   make(chan queryJob),
+		closed: make(chan struct{}),
+		query:  graph.downstream,
+	}
+	e.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go e.work()
+	}
+	return e
+}
+
+// Processes queued jobs until the engine is closed.
+func (e *QueryEngine) work() {
+	defer e.wg.Done()
+	for {
+		select {
+		case <-e.closed:
+			return
+		case job := <-e.jobs:
+			if job.ctx.Err() != nil {
+				// the caller has given up, skip the traversal
+				job.result <- queryResult{err: job.ctx.Err()}
+				continue
+			}
+			paths, err := e.query(job.paths)
+			job.result <- queryResult{paths: paths, err: err}
+		}
+	}
+}
+
+// Downstream gets all the downstream nodes for the given paths once a
+// worker is free. Returns the context's error if it is done before the
+// query is answered.
+func (e *QueryEngine) Downstream(ctx context.Context, paths []string) ([]string, error) {
+	// buffered so that a worker never blocks on an abandoned job
+	job := queryJob{ctx: ctx, paths: paths, result: make(chan queryResult, 1)}
+	select {
+	case e.jobs <- job:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-e.closed:
+		return nil, ErrEngineClosed
+	}
+	select {
+	case result := <-job.result:
+		return result.paths, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the workers after their current queries. Queries issued
+// after Close return ErrEngineClosed.
+func (e *QueryEngine) Close() {
+	e.once.Do(func() {
+		close(e.closed)
+	})
+	e.wg.Wait()
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestQueryEngine issues many concurrent queries and checks that the
+// results are correct and that no more queries than workers run at
+// once. Run with -race.
+func TestQueryEngine(t *testing.T) {
+	graph := jaffleShopGraph()
+	engine := NewQueryEngine(graph, 3)
+	defer engine.Close()
+
+	// wrap the traversal to track the number of running queries
+	var running, peak int32
+	engine.query = func(paths []string) ([]string, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		defer atomic.AddInt32(&running, -1)
+		return graph.downstream(paths)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			downstream, err := engine.Downstream(context.Background(), []string{"stg_orders"})
+			if err != nil {
+				errs <- err.Error()
+				return
+			}
+			sort.Strings(downstream)
+			if found := strings.Join(downstream, ","); found != "dim_customers,fct_orders,weekly_jaffle_metrics" {
+				errs <- "unexpected downstream " + found
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Query failed - %v", err)
+	}
+	if peak > 3 {
+		t.Fatalf("Concurrency mismatch. Expected at most %d, Found %d", 3, peak)
+	}
+
+	// a cancelled context is reported to the caller
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := engine.Downstream(ctx, []string{"stg_orders"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context error, Found %v", err)
+	}
+
+	engine.Close()
+	if _, err := engine.Downstream(context.Background(), []string{"stg_orders"}); !errors.Is(err, ErrEngineClosed) {
+		t.Fatalf("Expected closed engine error, Found %v", err)
+	}
+}
@@ -0,0 +1,377 @@
+package graph
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dotEscaper escapes the characters that are special inside a quoted
+// DOT identifier.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// Returns the path as a quoted DOT identifier.
+func dotQuote(path string) string {
+	return `"` + dotEscaper.Replace(path) + `"`
+}
+
+// ToDOT writes the graph as a Graphviz DOT digraph, e.g. to pipe to
+// dot -Tpng. Every path is quoted, so the colons and dots in paths are
+// kept, and nodes and edges are written in path order so the output is
+// stable across runs.
+func (g *Graph) ToDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph {")
+	for _, path := range g.sortedPaths() {
+		fmt.Fprintf(bw, "  %s;\n", dotQuote(path))
+	}
+	for _, edge := range g.edges() {
+		fmt.Fprintf(bw, "  %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteDiffDOT writes the lineage changes from the old graph to the new
+// graph as a Graphviz DOT digraph. Only the changed edges and the nodes
+// they connect are written. Added edges and nodes are green, removed
+// edges and nodes are red and dashed, and unchanged nodes that provide
+// context for a changed edge have no attributes.
+func WriteDiffDOT(w io.Writer, old, new *Graph) error {
+	diff := Diff(old, new)
+	nodeAttrs := map[string]string{}
+	for _, path := range diff.AddedNodes {
+		nodeAttrs[path] = " [color=green]"
+	}
+	for _, path := range diff.RemovedNodes {
+		nodeAttrs[path] = " [color=red, style=dashed]"
+	}
+	for _, edges := range [][]Edge{diff.AddedEdges, diff.RemovedEdges} {
+		for _, edge := range edges {
+			for _, path := range []string{edge.From, edge.To} {
+				if _, ok := nodeAttrs[path]; !ok {
+					nodeAttrs[path] = ""
+				}
+			}
+		}
+	}
+	paths := make([]string, 0, len(nodeAttrs))
+	for path := range nodeAttrs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph {")
+	for _, path := range paths {
+		fmt.Fprintf(bw, "  %s%s;\n", dotQuote(path), nodeAttrs[path])
+	}
+	for _, edge := range diff.AddedEdges {
+		fmt.Fprintf(bw, "  %s -> %s [color=green];\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+	for _, edge := range diff.RemovedEdges {
+		fmt.Fprintf(bw, "  %s -> %s [color=red, style=dashed];\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteImpactReportCsv writes a CSV report with the direct degree and
+// the transitive upstream and downstream counts of every node, sorted
+// by path, under the header
+// path,in_degree,out_degree,upstream_count,downstream_count.
+func (g *Graph) WriteImpactReportCsv(w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	header := []string{"path", "in_degree", "out_degree", "upstream_count", "downstream_count"}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+	for _, path := range g.sortedPaths() {
+		node := g.nodes[path]
+		upstream, err := g.upstream([]string{path})
+		if err != nil {
+			return err
+		}
+		downstream, err := g.downstream([]string{path})
+		if err != nil {
+			return err
+		}
+		record := []string{
+			path,
+			strconv.Itoa(len(node.upstream)),
+			strconv.Itoa(len(node.downstream)),
+			strconv.Itoa(len(upstream)),
+			strconv.Itoa(len(downstream)),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// cytoscapeNode and cytoscapeEdge are the elements written by
+// WriteCytoscapeJSON.
+type cytoscapeNode struct {
+	ID string `json:"id"`
+}
+
+type cytoscapeEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// WriteCytoscapeJSON writes the graph as a JSON object of the form
+// {"nodes": [{"id": ...}], "edges": [{"source": ..., "target": ...}]}
+// for front-end libraries like Cytoscape.js and D3. Nodes are sorted
+// by path and edges by source and target.
+func (g *Graph) WriteCytoscapeJSON(w io.Writer) error {
+	out := struct {
+		Nodes []cytoscapeNode `json:"nodes"`
+		Edges []cytoscapeEdge `json:"edges"`
+	}{
+		Nodes: []cytoscapeNode{},
+		Edges: []cytoscapeEdge{},
+	}
+	for _, path := range g.sortedPaths() {
+		out.Nodes = append(out.Nodes, cytoscapeNode{ID: path})
+	}
+	for _, edge := range g.edges() {
+		out.Edges = append(out.Edges, cytoscapeEdge{Source: edge.From, Target: edge.To})
+	}
+	return json.NewEncoder(w).Encode(out)
+}
+
+// WriteJSONLines writes every edge as a JSON object of the form
+// {"source": ..., "target": ...} on its own line, sorted by source and
+// target, which NewGraphFromJSONLines reads back. Isolated nodes have
+// no edge and are not written.
+func (g *Graph) WriteJSONLines(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	for _, edge := range g.edges() {
+		if err := encoder.Encode(JSONLine{Source: edge.From, Target: edge.To}); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// openLineageNamespace is the namespace of the datasets and jobs
+// written by WriteOpenLineage.
+const openLineageNamespace = "synq-graphs"
+
+// openLineageDataset, openLineageJob, openLineageRun and
+// openLineageEvent are the subset of the OpenLineage RunEvent written
+// by WriteOpenLineage.
+type openLineageDataset struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type openLineageJob struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type openLineageRun struct {
+	RunID string `json:"runId"`
+}
+
+type openLineageEvent struct {
+	EventType string               `json:"eventType"`
+	EventTime string               `json:"eventTime"`
+	Run       openLineageRun       `json:"run"`
+	Job       openLineageJob       `json:"job"`
+	Inputs    []openLineageDataset `json:"inputs"`
+	Outputs   []openLineageDataset `json:"outputs"`
+	Producer  string               `json:"producer"`
+	SchemaURL string               `json:"schemaURL"`
+}
+
+// Returns a name based UUID for the run of the job that produces the
+// path, so that the same graph always gets the same run IDs.
+func openLineageRunID(path string) string {
+	sum := sha1.Sum([]byte(openLineageNamespace + "/" + path))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// WriteOpenLineage writes the dataset to dataset lineage of the graph
+// as OpenLineage COMPLETE run events, one JSON object per line, for
+// tools like Marquez. Every node with upstream relations gets a job
+// named after it, with its upstream nodes as the input datasets and
+// the node itself as the output dataset. Events are sorted by node
+// and all datasets are in the synq-graphs namespace. Every event is
+// stamped with eventTime, so the same graph and time always give the
+// same output.
+func (g *Graph) WriteOpenLineage(w io.Writer, eventTime time.Time) error {
+	stamp := eventTime.UTC().Format(time.RFC3339)
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	for _, path := range g.sortedPaths() {
+		node := g.nodes[path]
+		if len(node.upstream) == 0 {
+			continue
+		}
+		upstream := append([]string{}, node.upstream...)
+		sort.Strings(upstream)
+		inputs := make([]openLineageDataset, 0, len(upstream))
+		for _, up := range upstream {
+			inputs = append(inputs, openLineageDataset{Namespace: openLineageNamespace, Name: up})
+		}
+		event := openLineageEvent{
+			EventType: "COMPLETE",
+			EventTime: stamp,
+			Run:       openLineageRun{RunID: openLineageRunID(path)},
+			Job:       openLineageJob{Namespace: openLineageNamespace, Name: path},
+			Inputs:    inputs,
+			Outputs:   []openLineageDataset{{Namespace: openLineageNamespace, Name: path}},
+			Producer:  "https://github.com/grasskode/synq-graphs",
+			SchemaURL: "https://openlineage.io/spec/2-0-2/OpenLineage.json#/$defs/RunEvent",
+		}
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Returns a PlantUML alias for every node. Characters that are not
+// letters, digits or underscores are replaced by underscores, and
+// aliases that collide after sanitizing get a numeric suffix.
+func (g *Graph) plantUMLAliases() map[string]string {
+	aliases := make(map[string]string, len(g.nodes))
+	taken := make(map[string]bool, len(g.nodes))
+	for _, path := range g.sortedPaths() {
+		alias := []rune{}
+		for _, r := range path {
+			if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				alias = append(alias, r)
+			} else {
+				alias = append(alias, '_')
+			}
+		}
+		base := "n_" + string(alias)
+		unique := base
+		for i := 2; taken[unique]; i++ {
+			unique = base + "_" + strconv.Itoa(i)
+		}
+		taken[unique] = true
+		aliases[path] = unique
+	}
+	return aliases
+}
+
+// plantUMLEscaper replaces the characters PlantUML cannot take as is
+// in a quoted label with their <U+XXXX> escapes. A backslash would
+// otherwise start an escape such as \n, and a quote cannot be escaped
+// with a backslash.
+var plantUMLEscaper = strings.NewReplacer(`\`, "<U+005C>", `"`, "<U+0022>")
+
+// Returns the path as a quoted PlantUML label.
+func plantUMLQuote(path string) string {
+	return `"` + plantUMLEscaper.Replace(path) + `"`
+}
+
+// WritePlantUML writes the graph as a PlantUML diagram with one
+// rectangle per node, labelled with its path, and one arrow per edge.
+// Nodes are sorted by path and edges by source and target.
+func (g *Graph) WritePlantUML(w io.Writer) error {
+	aliases := g.plantUMLAliases()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "@startuml")
+	for _, path := range g.sortedPaths() {
+		fmt.Fprintf(bw, "rectangle %s as %s\n", plantUMLQuote(path), aliases[path])
+	}
+	for _, edge := range g.edges() {
+		fmt.Fprintf(bw, "%s --> %s\n", aliases[edge.From], aliases[edge.To])
+	}
+	fmt.Fprintln(bw, "@enduml")
+	return bw.Flush()
+}
+
+// Returns the paths sorted and joined by commas for a Markdown table
+// cell, with pipes escaped.
+func markdownCell(paths []string) string {
+	sorted := append([]string{}, paths...)
+	sort.Strings(sorted)
+	return strings.ReplaceAll(strings.Join(sorted, ", "), "|", `\|`)
+}
+
+// WriteMarkdownTable writes a Markdown table with a row for every node,
+// sorted by path, and its direct upstream and downstream nodes.
+func (g *Graph) WriteMarkdownTable(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "| node | upstream | downstream |")
+	fmt.Fprintln(bw, "| --- | --- | --- |")
+	for _, path := range g.sortedPaths() {
+		node := g.nodes[path]
+		fmt.Fprintf(bw, "| %s | %s | %s |\n", markdownCell([]string{path}),
+			markdownCell(node.upstream), markdownCell(node.downstream))
+	}
+	return bw.Flush()
+}
+
+// gexfNode and gexfEdge are the node and edge elements of the GEXF
+// output.
+type gexfNode struct {
+	ID    string `xml:"id,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type gexfEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+// WriteGEXF writes the graph as a GEXF 1.3 document for Gephi, with
+// directed edges and nodes labelled with their paths. Nodes are sorted
+// by path and numbered from n0, and edges are sorted by source and
+// target and numbered from e0, so the output is deterministic.
+func (g *Graph) WriteGEXF(w io.Writer) error {
+	type gexfGraph struct {
+		DefaultEdgeType string     `xml:"defaultedgetype,attr"`
+		Nodes           []gexfNode `xml:"nodes>node"`
+		Edges           []gexfEdge `xml:"edges>edge"`
+	}
+	out := struct {
+		XMLName xml.Name  `xml:"gexf"`
+		XMLNS   string    `xml:"xmlns,attr"`
+		Version string    `xml:"version,attr"`
+		Graph   gexfGraph `xml:"graph"`
+	}{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Graph:   gexfGraph{DefaultEdgeType: "directed"},
+	}
+	ids := make(map[string]string, len(g.nodes))
+	for i, path := range g.sortedPaths() {
+		ids[path] = "n" + strconv.Itoa(i)
+		out.Graph.Nodes = append(out.Graph.Nodes, gexfNode{ID: ids[path], Label: path})
+	}
+	for i, edge := range g.edges() {
+		out.Graph.Edges = append(out.Graph.Edges, gexfEdge{ID: "e" + strconv.Itoa(i), Source: ids[edge.From], Target: ids[edge.To]})
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	encoder := xml.NewEncoder(bw)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return err
+	}
+	bw.WriteString("\n")
+	return bw.Flush()
+}
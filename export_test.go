@@ -0,0 +1,293 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWriteImpactReportCsv asserts the report rows for the jaffle_shop
+// graph.
+func TestWriteImpactReportCsv(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	var out strings.Builder
+	if err := graph.WriteImpactReportCsv(&out); err != nil {
+		t.Fatalf("Error writing report - %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 11 {
+		t.Fatalf("Line count mismatch. Expected %d, Found %d", 11, len(lines))
+	}
+	if lines[0] != "path,in_degree,out_degree,upstream_count,downstream_count" {
+		t.Fatalf("Header mismatch. Found %v", lines[0])
+	}
+	// rows are sorted by path
+	if !strings.HasPrefix(lines[1], "dim_customers,") {
+		t.Fatalf("Row order mismatch. Found %v", lines[1])
+	}
+	if !contains(lines, "fct_orders,2,1,4,1") {
+		t.Fatalf("Expected row %s in report\n%s", "fct_orders,2,1,4,1", out.String())
+	}
+}
+
+// TestWriteCytoscapeJSON decodes the output for the jaffle_shop graph
+// and checks the node and edge counts.
+func TestWriteCytoscapeJSON(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	var out strings.Builder
+	if err := graph.WriteCytoscapeJSON(&out); err != nil {
+		t.Fatalf("Error writing JSON - %v", err)
+	}
+	var decoded struct {
+		Nodes []struct {
+			ID string `json:"id"`
+		} `json:"nodes"`
+		Edges []struct {
+			Source string `json:"source"`
+			Target string `json:"target"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Fatalf("Unable to decode output - %v", err)
+	}
+	if len(decoded.Nodes) != 10 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 10, len(decoded.Nodes))
+	}
+	if len(decoded.Edges) != 10 {
+		t.Fatalf("Edge count mismatch. Expected %d, Found %d", 10, len(decoded.Edges))
+	}
+	if decoded.Nodes[0].ID != "dim_customers" || decoded.Edges[0].Source != "dim_customers" {
+		t.Fatalf("Order mismatch. Found %v and %v", decoded.Nodes[0], decoded.Edges[0])
+	}
+}
+
+// TestWriteJSONLines writes the jaffle_shop graph as JSON lines and
+// asserts that reading it back gives an equal graph.
+func TestWriteJSONLines(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	var out strings.Builder
+	if err := graph.WriteJSONLines(&out); err != nil {
+		t.Fatalf("Error writing JSON lines - %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	expected := `{"source":"dim_customers","target":"weekly_jaffle_metrics"}`
+	if len(lines) != 10 || lines[0] != expected {
+		t.Fatalf("Expected 10 lines starting with %s, Found\n%s", expected, out.String())
+	}
+
+	reloaded, err := NewGraphFromJSONLines(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatalf("Unable to read JSON lines - %v", err)
+	}
+	if !reloaded.Equal(graph) {
+		t.Fatalf("Expected the reloaded graph to equal the original")
+	}
+}
+
+// TestWritePlantUML asserts the header, footer and arrows of the
+// PlantUML output for the jaffle_shop graph.
+func TestWritePlantUML(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	var out strings.Builder
+	if err := graph.WritePlantUML(&out); err != nil {
+		t.Fatalf("Error writing PlantUML - %v", err)
+	}
+	uml := out.String()
+	if !strings.HasPrefix(uml, "@startuml\n") || !strings.HasSuffix(uml, "@enduml\n") {
+		t.Fatalf("Expected @startuml and @enduml\n%s", uml)
+	}
+	if arrows := strings.Count(uml, " --> "); arrows != 10 {
+		t.Fatalf("Arrow count mismatch. Expected %d, Found %d", 10, arrows)
+	}
+	expected := []string{
+		`rectangle "jaffle_shop.orders" as n_jaffle_shop_orders`,
+		`n_jaffle_shop_orders --> n_stg_orders`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(uml, line+"\n") {
+			t.Fatalf("Expected line %s in output\n%s", line, uml)
+		}
+	}
+
+	var again strings.Builder
+	if err := graph.WritePlantUML(&again); err != nil || again.String() != uml {
+		t.Fatalf("Expected deterministic output")
+	}
+
+	// sanitized aliases that collide stay unique
+	collide := &Graph{}
+	collide.insert("a.b", "a-b")
+	aliases := collide.plantUMLAliases()
+	if aliases["a.b"] == aliases["a-b"] {
+		t.Fatalf("Expected unique aliases, Found %v", aliases)
+	}
+
+	// quotes and backslashes in paths are escaped for PlantUML
+	quoted := plantUMLQuote(`s3://"raw"\orders`)
+	if expected := `"s3://<U+0022>raw<U+0022><U+005C>orders"`; quoted != expected {
+		t.Fatalf("Quoted label mismatch. Expected %v, Found %v", expected, quoted)
+	}
+}
+
+// TestWriteGEXF asserts the root element and the node and edge counts
+// of the GEXF output for the jaffle_shop graph.
+func TestWriteGEXF(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	var out strings.Builder
+	if err := graph.WriteGEXF(&out); err != nil {
+		t.Fatalf("Error writing GEXF - %v", err)
+	}
+	var decoded struct {
+		XMLName xml.Name
+		Version string `xml:"version,attr"`
+		Graph   struct {
+			DefaultEdgeType string     `xml:"defaultedgetype,attr"`
+			Nodes           []gexfNode `xml:"nodes>node"`
+			Edges           []gexfEdge `xml:"edges>edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Fatalf("Unable to decode GEXF - %v", err)
+	}
+	if decoded.XMLName.Space != "http://gexf.net/1.3" || decoded.XMLName.Local != "gexf" || decoded.Version != "1.3" {
+		t.Fatalf("Root element mismatch. Found %v version %s", decoded.XMLName, decoded.Version)
+	}
+	if decoded.Graph.DefaultEdgeType != "directed" {
+		t.Fatalf("Edge type mismatch. Expected %v, Found %v", "directed", decoded.Graph.DefaultEdgeType)
+	}
+	if len(decoded.Graph.Nodes) != 10 || len(decoded.Graph.Edges) != 10 {
+		t.Fatalf("Expected 10 nodes and 10 edges, Found %d and %d", len(decoded.Graph.Nodes), len(decoded.Graph.Edges))
+	}
+	// dim_customers is the first node and its only edge is the first
+	first := decoded.Graph.Edges[0]
+	if decoded.Graph.Nodes[0].Label != "dim_customers" || first.Source != "n0" {
+		t.Fatalf("Order mismatch. Found %v and %v", decoded.Graph.Nodes[0], first)
+	}
+
+	var again strings.Builder
+	if err := graph.WriteGEXF(&again); err != nil || again.String() != out.String() {
+		t.Fatalf("Expected deterministic output")
+	}
+}
+
+// TestWriteMarkdownTable compares the Markdown table for the
+// jaffle_shop graph against the expected output.
+func TestWriteMarkdownTable(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	var out strings.Builder
+	if err := graph.WriteMarkdownTable(&out); err != nil {
+		t.Fatalf("Error writing Markdown table - %v", err)
+	}
+	expected := `| node | upstream | downstream |
+| --- | --- | --- |
+| dim_customers | stg_customers, stg_orders | weekly_jaffle_metrics |
+| fct_orders | stg_orders, stg_payments | weekly_jaffle_metrics |
+| gsheets.goals |  | weekly_jaffle_metrics |
+| jaffle_shop.customers |  | stg_customers |
+| jaffle_shop.orders |  | stg_orders |
+| stg_customers | jaffle_shop.customers | dim_customers |
+| stg_orders | jaffle_shop.orders | dim_customers, fct_orders |
+| stg_payments | stripe.payment | fct_orders |
+| stripe.payment |  | stg_payments |
+| weekly_jaffle_metrics | dim_customers, fct_orders, gsheets.goals |  |
+`
+	if table := out.String(); table != expected {
+		t.Fatalf("Markdown table mismatch. Expected\n%s\nFound\n%s", expected, table)
+	}
+}
+
+// TestWriteOpenLineage decodes the OpenLineage events for the
+// jaffle_shop graph, asserting the RunEvent field names and that every
+// dataset is written.
+func TestWriteOpenLineage(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	eventTime := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	var out strings.Builder
+	if err := graph.WriteOpenLineage(&out, eventTime); err != nil {
+		t.Fatalf("Error writing OpenLineage - %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	// one event for every node with upstream relations
+	if len(lines) != 6 {
+		t.Fatalf("Event count mismatch. Expected %d, Found %d", 6, len(lines))
+	}
+	datasets := make(map[string]bool)
+	for _, line := range lines {
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Unable to decode event %s - %v", line, err)
+		}
+		for _, field := range []string{"eventType", "eventTime", "run", "job", "inputs", "outputs", "producer", "schemaURL"} {
+			if _, ok := event[field]; !ok {
+				t.Fatalf("Expected field %s in event %s", field, line)
+			}
+		}
+		if _, ok := event["run"].(map[string]interface{})["runId"]; !ok {
+			t.Fatalf("Expected run.runId in event %s", line)
+		}
+		for _, key := range []string{"inputs", "outputs"} {
+			for _, dataset := range event[key].([]interface{}) {
+				fields := dataset.(map[string]interface{})
+				if fields["namespace"] != openLineageNamespace {
+					t.Fatalf("Namespace mismatch. Expected %v, Found %v", openLineageNamespace, fields["namespace"])
+				}
+				datasets[fields["name"].(string)] = true
+			}
+		}
+	}
+	for _, path := range graph.sortedPaths() {
+		if !datasets[path] {
+			t.Fatalf("Expected dataset %s in output", path)
+		}
+	}
+	expected := `{"eventType":"COMPLETE","eventTime":"2023-06-01T12:00:00Z",` +
+		`"run":{"runId":"` + openLineageRunID("dim_customers") + `"},` +
+		`"job":{"namespace":"synq-graphs","name":"dim_customers"},` +
+		`"inputs":[{"namespace":"synq-graphs","name":"stg_customers"},{"namespace":"synq-graphs","name":"stg_orders"}],` +
+		`"outputs":[{"namespace":"synq-graphs","name":"dim_customers"}],` +
+		`"producer":"https://github.com/grasskode/synq-graphs",` +
+		`"schemaURL":"https://openlineage.io/spec/2-0-2/OpenLineage.json#/$defs/RunEvent"}`
+	if lines[0] != expected {
+		t.Fatalf("Event mismatch. Expected %v, Found %v", expected, lines[0])
+	}
+
+	var again strings.Builder
+	if err := graph.WriteOpenLineage(&again, eventTime); err != nil || again.String() != out.String() {
+		t.Fatalf("Expected deterministic output")
+	}
+}
+
+// TestToDOT compares the DOT output for a small graph with quoted
+// paths against the expected digraph.
+func TestToDOT(t *testing.T) {
+	graph := &Graph{}
+	graph.insert("dbt-sh-prod::model.ops.stg_runs", "dbt-sh-prod::model.ops.fct_runs")
+	graph.insert("raw.\"runs\"", "dbt-sh-prod::model.ops.stg_runs")
+	graph.getOrCreate("isolated")
+
+	var out strings.Builder
+	if err := graph.ToDOT(&out); err != nil {
+		t.Fatalf("Error writing DOT - %v", err)
+	}
+	expected := `digraph {
+  "dbt-sh-prod::model.ops.fct_runs";
+  "dbt-sh-prod::model.ops.stg_runs";
+  "isolated";
+  "raw.\"runs\"";
+  "dbt-sh-prod::model.ops.stg_runs" -> "dbt-sh-prod::model.ops.fct_runs";
+  "raw.\"runs\"" -> "dbt-sh-prod::model.ops.stg_runs";
+}
+`
+	if dot := out.String(); dot != expected {
+		t.Fatalf("DOT mismatch. Expected\n%s\nFound\n%s", expected, dot)
+	}
+}
@@ -0,0 +1,163 @@
+package graph
+
+import "sort"
+
+// EdgeDisjointPaths returns the maximum number of downstream paths
+// from one node to another that share no edge. By Menger's theorem
+// this is the maximum flow between the nodes when every edge has a
+// capacity of one, computed with Ford-Fulkerson using breadth first
+// augmenting paths.
+func (g *Graph) EdgeDisjointPaths(from, to string) (int, error) {
+	if _, ok := g.nodes[from]; !ok {
+		return 0, &MissingNodeError{path: from}
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return 0, &MissingNodeError{path: to}
+	}
+	if from == to {
+		return 0, nil
+	}
+
+	flow := make(map[Edge]bool)
+	paths := 0
+	for {
+		// step records how each node was reached in the residual
+		// graph: along an edge with spare capacity or backwards
+		// along an edge that carries flow
+		type step struct {
+			prev     string
+			backward bool
+		}
+		steps := map[string]step{from: {}}
+		queue := []string{from}
+		for len(queue) > 0 {
+			if _, ok := steps[to]; ok {
+				break
+			}
+			path := queue[0]
+			queue = queue[1:]
+			node := g.nodes[path]
+			for _, ds := range node.downstream {
+				if _, ok := steps[ds]; !ok && !flow[Edge{From: path, To: ds}] {
+					steps[ds] = step{prev: path}
+					queue = append(queue, ds)
+				}
+			}
+			for _, up := range node.upstream {
+				if _, ok := steps[up]; !ok && flow[Edge{From: up, To: path}] {
+					steps[up] = step{prev: path, backward: true}
+					queue = append(queue, up)
+				}
+			}
+		}
+		if _, ok := steps[to]; !ok {
+			return paths, nil
+		}
+
+		// push one unit of flow along the augmenting path
+		for path := to; path != from; path = steps[path].prev {
+			s := steps[path]
+			if s.backward {
+				delete(flow, Edge{From: path, To: s.prev})
+			} else {
+				flow[Edge{From: s.prev, To: path}] = true
+			}
+		}
+		paths++
+	}
+}
+
+// MinRootCut returns a smallest set of nodes whose removal leaves the
+// target unreachable from every root upstream of it. Each ancestor is
+// split into an entry and an exit joined by a capacity of one, so that
+// the minimum cut of the maximum flow from the roots to the target is
+// a minimum vertex cut. Of the minimum cuts, the one closest to the
+// roots is returned, so roots that independently feed the target are
+// cut themselves. The result is sorted and is empty if the target has
+// no upstream.
+func (g *Graph) MinRootCut(target string) ([]string, error) {
+	ancestors, err := g.upstream([]string{target})
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{}
+	for _, a := range ancestors {
+		// the target is its own ancestor if it is on a cycle
+		if a != target {
+			paths = append(paths, a)
+		}
+	}
+	if len(paths) == 0 {
+		return []string{}, nil
+	}
+	paths = append(paths, target)
+	index := make(map[string]int, len(paths))
+	for i, path := range paths {
+		index[path] = i
+	}
+	// node i enters at 2i and exits at 2i+1, the flow starts at source
+	// and ends at the entry of the target
+	source, sink := 2*len(paths), 2*index[target]
+	infinite := len(paths) + 1
+	capacity := make(map[[2]int]int)
+	adjacent := make(map[int][]int)
+	link := func(from, to, c int) {
+		capacity[[2]int{from, to}] += c
+		adjacent[from] = append(adjacent[from], to)
+		adjacent[to] = append(adjacent[to], from)
+	}
+	for i, path := range paths {
+		node := g.nodes[path]
+		if path != target {
+			link(2*i, 2*i+1, 1)
+		}
+		if len(node.upstream) == 0 {
+			link(source, 2*i, infinite)
+		}
+		for _, ds := range node.downstream {
+			if j, ok := index[ds]; ok {
+				link(2*i+1, 2*j, infinite)
+			}
+		}
+	}
+
+	// residual walk from the source, recording how each vertex was
+	// reached
+	residual := func() map[int]int {
+		prev := map[int]int{source: source}
+		queue := []int{source}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			for _, u := range adjacent[v] {
+				if _, ok := prev[u]; !ok && capacity[[2]int{v, u}] > 0 {
+					prev[u] = v
+					queue = append(queue, u)
+				}
+			}
+		}
+		return prev
+	}
+	for {
+		prev := residual()
+		if _, ok := prev[sink]; !ok {
+			// the exits that cannot be reached behind reachable
+			// entries are the cut nodes
+			cut := []string{}
+			for i, path := range paths {
+				_, in := prev[2*i]
+				_, out := prev[2*i+1]
+				if in && !out && path != target {
+					cut = append(cut, path)
+				}
+			}
+			sort.Strings(cut)
+			return cut, nil
+		}
+		// every augmenting path crosses a split node, so push one unit
+		for v := sink; v != source; v = prev[v] {
+			capacity[[2]int{prev[v], v}]--
+			capacity[[2]int{v, prev[v]}]++
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestEdgeDisjointPaths asserts the number of edge-disjoint paths for
+// two separate routes and for a single chain.
+func TestEdgeDisjointPaths(t *testing.T) {
+	// two routes a -> b -> d and a -> c -> d, plus a crossing b -> c
+	// that does not add a third disjoint path
+	graph := &Graph{}
+	graph.insert("a", "b")
+	graph.insert("a", "c")
+	graph.insert("b", "d")
+	graph.insert("c", "d")
+	graph.insert("b", "c")
+
+	count, err := graph.EdgeDisjointPaths("a", "d")
+	if err != nil {
+		t.Fatalf("Error counting paths - %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("Path count mismatch. Expected %d, Found %d", 2, count)
+	}
+
+	chain := &Graph{}
+	chain.insert("a", "b")
+	chain.insert("b", "c")
+	count, err = chain.EdgeDisjointPaths("a", "c")
+	if err != nil {
+		t.Fatalf("Error counting paths - %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("Path count mismatch. Expected %d, Found %d", 1, count)
+	}
+
+	// the first augmenting path s -> a -> b -> t blocks the second
+	// route until the flow on a -> b is cancelled through the
+	// residual graph: s -> a -> d -> t and s -> c -> b -> t
+	residual := &Graph{}
+	for _, edge := range []Edge{{"s", "a"}, {"s", "c"}, {"a", "b"}, {"a", "d"}, {"c", "b"}, {"b", "t"}, {"d", "t"}} {
+		residual.insert(edge.From, edge.To)
+	}
+	count, err = residual.EdgeDisjointPaths("s", "t")
+	if err != nil {
+		t.Fatalf("Error counting paths - %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("Path count mismatch. Expected %d, Found %d", 2, count)
+	}
+
+	if _, err := chain.EdgeDisjointPaths("a", "missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestMinRootCut asserts that two sources independently feeding a
+// target must both be cut and that a single source is cut alone.
+func TestMinRootCut(t *testing.T) {
+	graph := &Graph{}
+	graph.insert("jaffle_shop.orders", "stg_orders")
+	graph.insert("stripe.payment", "stg_payments")
+	graph.insert("stg_orders", "fct_orders")
+	graph.insert("stg_payments", "fct_orders")
+
+	cut, err := graph.MinRootCut("fct_orders")
+	if err != nil {
+		t.Fatalf("Error getting min root cut - %v", err)
+	}
+	expected := "jaffle_shop.orders,stripe.payment"
+	if result := strings.Join(cut, ","); result != expected {
+		t.Fatalf("Cut mismatch. Expected %v, Found %v", expected, result)
+	}
+
+	// a single source feeding the target through two branches
+	graph = &Graph{}
+	graph.insert("jaffle_shop.orders", "stg_orders")
+	graph.insert("jaffle_shop.orders", "stg_order_items")
+	graph.insert("stg_orders", "fct_orders")
+	graph.insert("stg_order_items", "fct_orders")
+	cut, err = graph.MinRootCut("fct_orders")
+	if err != nil {
+		t.Fatalf("Error getting min root cut - %v", err)
+	}
+	if result := strings.Join(cut, ","); result != "jaffle_shop.orders" {
+		t.Fatalf("Cut mismatch. Expected %v, Found %v", "jaffle_shop.orders", result)
+	}
+
+	// the four sources of weekly_jaffle_metrics are separated by
+	// cutting its three immediate upstream nodes
+	cut, err = jaffleShopGraph().MinRootCut("weekly_jaffle_metrics")
+	if err != nil {
+		t.Fatalf("Error getting min root cut - %v", err)
+	}
+	expected = "dim_customers,fct_orders,gsheets.goals"
+	if result := strings.Join(cut, ","); result != expected {
+		t.Fatalf("Cut mismatch. Expected %v, Found %v", expected, result)
+	}
+
+	if cut, err := graph.MinRootCut("jaffle_shop.orders"); err != nil || len(cut) != 0 {
+		t.Fatalf("Expected an empty cut for a root, Found %v and %v", cut, err)
+	}
+	if _, err := graph.MinRootCut("missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
@@ -1,9 +1,8 @@
 package graph
 
 import (
-	"encoding/csv"
 	"fmt"
-	"os"
+	"sort"
 )
 
 // MissingNodeError is thrown when the graph cannot find
@@ -16,101 +15,152 @@ func (m *MissingNodeError) Error() string {
 	return fmt.Sprintf("missing node for path %s", m.path)
 }
 
+// CycleError is thrown when an operation requires the graph to be
+// acyclic but the graph contains a cycle through the path.
+type CycleError struct {
+	path string
+}
+
+func (c *CycleError) Error() string {
+	return fmt.Sprintf("cycle detected through path %s", c.path)
+}
+
+// Edge represents a single relation in the graph from an upstream
+// node to a downstream node.
+type Edge struct {
+	From string
+	To   string
+}
+
 // Node represents a single node in the graph. It contains
-// the path of the node and the immediate upstream and
-// downstream relations.
+// the path of the node, its optional kind and the immediate
+// upstream and downstream relations.
 type Node struct {
 	path       string
+	kind       string
 	upstream   []string
 	downstream []string
 }
 
 // Graph stores the graph representation and exposes
 // the functions used to traverse lineage. It stores
-// the nodes mapped by their paths.
+// the nodes mapped by their paths, and the optional attributes
+// of edges mapped by the edge.
 type Graph struct {
 	nodes map[string]*Node
+	attrs map[Edge]*EdgeAttrs
+}
+
+// NewGraph returns an empty graph ready for inserts.
+func NewGraph() *Graph {
+	return &Graph{}
+}
+
+// Upstream returns all the upstream nodes of the given paths, or a
+// MissingNodeError if a path is not in the graph.
+func (g *Graph) Upstream(paths []string) ([]string, error) {
+	return g.upstream(paths)
+}
+
+// Downstream returns all the downstream nodes of the given paths, or a
+// MissingNodeError if a path is not in the graph.
+func (g *Graph) Downstream(paths []string) ([]string, error) {
+	return g.downstream(paths)
+}
+
+// UpstreamN gets the upstream nodes of the given paths like Upstream,
+// but stops expanding beyond depth hops from the paths: a depth of 0
+// returns only the immediate upstream relations, and a negative depth
+// does not limit the traversal.
+func (g *Graph) UpstreamN(paths []string, depth int) ([]string, error) {
+	result, _, err := g.traverse(paths, upstreamOf, depth)
+	return result, err
+}
+
+// DownstreamN gets the downstream nodes of the given paths like
+// Downstream, but stops expanding beyond depth hops from the paths: a
+// depth of 0 returns only the immediate downstream relations, and a
+// negative depth does not limit the traversal.
+func (g *Graph) DownstreamN(paths []string, depth int) ([]string, error) {
+	result, _, err := g.traverse(paths, downstreamOf, depth)
+	return result, err
 }
 
 // Gets all the upstream nodes in the graph for the given paths.
 func (g *Graph) upstream(paths []string) ([]string, error) {
-	found := make(map[string]bool)
-	processed := []string{}
-	for {
-		if len(paths) == 0 {
-			break
-		}
-		path := paths[0]
-		paths = paths[1:]
-		if contains(processed, path) {
-			// skip path if it is already processed
-			continue
-		}
-		node, ok := g.nodes[path]
-		if !ok {
-			return nil, &MissingNodeError{path: path}
-		}
-		// push node's upstream relations to process
-		paths = append(paths, node.upstream...)
-		// add upstreams to found
-		for _, up := range node.upstream {
-			found[up] = true
-		}
-		// mark path as processed
-		processed = append(processed, path)
-	}
-
-	// return the keys of the found nodes
-	result := make([]string, len(found))
-	i := 0
-	for k := range found {
-		result[i] = k
-		i++
-	}
-	return result, nil
+	result, _, err := g.traverse(paths, upstreamOf, -1)
+	return result, err
 }
 
 // Gets all the downstream nodes in the graph for the given paths.
 func (g *Graph) downstream(paths []string) ([]string, error) {
+	result, _, err := g.traverse(paths, downstreamOf, -1)
+	return result, err
+}
+
+// Gets all the nodes reachable from the given paths along the
+// relations selected by next, and the number of node lookups made.
+// The visited set is seeded with all the paths up front, so a path
+// that is also reached from another path is only expanded once and
+// every node is looked up at most once. Nodes more than depth hops
+// from the paths are not expanded, so the result ends depth+1 hops
+// away; a negative depth does not limit the traversal.
+func (g *Graph) traverse(paths []string, next func(*Node) []string, depth int) (result []string, lookups int, err error) {
+	return traverseWith(g.lookup, paths, next, depth)
+}
+
+// Traverses like Graph.traverse, reading every node with lookup.
+func traverseWith(lookup func(path string) (*Node, bool, error), paths []string, next func(*Node) []string, depth int) (result []string, lookups int, err error) {
+	// queued pairs a path with its hop distance from the given paths
+	type queued struct {
+		path string
+		hops int
+	}
 	found := make(map[string]bool)
-	processed := []string{}
-	for {
-		if len(paths) == 0 {
-			break
+	visited := make(map[string]bool, len(paths))
+	queue := make([]queued, 0, len(paths))
+	for _, path := range paths {
+		if !visited[path] {
+			visited[path] = true
+			queue = append(queue, queued{path: path})
 		}
-		path := paths[0]
-		paths = paths[1:]
-		if contains(processed, path) {
-			// skip path if it is already processed
-			continue
+	}
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+		node, ok, err := lookup(item.path)
+		lookups++
+		if err != nil {
+			return nil, lookups, err
 		}
-		node, ok := g.nodes[path]
 		if !ok {
-			return nil, &MissingNodeError{path: path}
+			return nil, lookups, &MissingNodeError{path: item.path}
 		}
-		// push node's downstream relations to process
-		paths = append(paths, node.downstream...)
-		// add downstreams to found
-		for _, up := range node.downstream {
-			found[up] = true
+		for _, n := range next(node) {
+			found[n] = true
+			// push relations that were not visited yet to process,
+			// unless they are beyond the depth
+			if !visited[n] && (depth < 0 || item.hops < depth) {
+				visited[n] = true
+				queue = append(queue, queued{path: n, hops: item.hops + 1})
+			}
 		}
-		// mark path as processed
-		processed = append(processed, path)
 	}
 
 	// return the keys of the found nodes
-	result := make([]string, len(found))
-	i := 0
+	result = make([]string, 0, len(found))
 	for k := range found {
-		result[i] = k
-		i++
+		result = append(result, k)
 	}
-	return result, nil
+	return result, lookups, nil
 }
 
 // Returns the node corresponding to the path. Creates one
 // if it does not exist.
 func (g *Graph) getOrCreate(path string) *Node {
+	if g.nodes == nil {
+		g.nodes = make(map[string]*Node)
+	}
 	node, ok := g.nodes[path]
 	if !ok {
 		node = &Node{
@@ -133,11 +183,8 @@ func contains(s []string, str string) bool {
 	return false
 }
 
-// Inserts the given relation to the graph.
+// Inserts the given relation to the graph, ignoring duplicates.
 func (g *Graph) insert(from string, to string) {
-	if g.nodes == nil {
-		g.nodes = make(map[string]*Node)
-	}
 	fromNode, toNode := g.getOrCreate(from), g.getOrCreate(to)
 	if !contains(fromNode.downstream, to) {
 		fromNode.downstream = append(fromNode.downstream, to)
@@ -147,6 +194,90 @@ func (g *Graph) insert(from string, to string) {
 	}
 }
 
+// Insert inserts the relation from the upstream path to the downstream
+// path, creating the nodes as needed. Inserting an existing relation
+// has no effect.
+func (g *Graph) Insert(from, to string) {
+	g.insert(from, to)
+}
+
+// InsertUpstream records that node depends on upstream, i.e. inserts
+// the relation from upstream to node.
+func (g *Graph) InsertUpstream(node, upstream string) {
+	g.insert(upstream, node)
+}
+
+// Checks if the graph has an edge between the given paths.
+func (g *Graph) hasEdge(from, to string) bool {
+	node, ok := g.nodes[from]
+	return ok && contains(node.downstream, to)
+}
+
+// AreAdjacent reports whether there is a direct edge between the two
+// paths in either direction.
+func (g *Graph) AreAdjacent(a, b string) bool {
+	return g.hasEdge(a, b) || g.hasEdge(b, a)
+}
+
+// Compact reallocates the relations of every node to their exact
+// length, releasing the spare capacity left by inserting edges. Call
+// it once the graph is loaded and before it is served read-only.
+func (g *Graph) Compact() {
+	for _, node := range g.nodes {
+		node.upstream = append(make([]string, 0, len(node.upstream)), node.upstream...)
+		node.downstream = append(make([]string, 0, len(node.downstream)), node.downstream...)
+	}
+}
+
+// Returns the paths of all the nodes in the graph in sorted order.
+func (g *Graph) sortedPaths() []string {
+	paths := make([]string, 0, len(g.nodes))
+	for path := range g.nodes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Returns all the edges in the graph sorted by their source and then
+// by their target.
+func (g *Graph) edges() []Edge {
+	edges := []Edge{}
+	for _, path := range g.sortedPaths() {
+		downstream := append([]string{}, g.nodes[path].downstream...)
+		sort.Strings(downstream)
+		for _, ds := range downstream {
+			edges = append(edges, Edge{From: path, To: ds})
+		}
+	}
+	return edges
+}
+
+// AdjacencyDown returns every node mapped to its immediate downstream
+// relations. The slices are sorted copies, so the map can be handed
+// to templates without exposing the graph.
+func (g *Graph) AdjacencyDown() map[string][]string {
+	return g.adjacency(downstreamOf)
+}
+
+// AdjacencyUp returns every node mapped to its immediate upstream
+// relations. The slices are sorted copies like AdjacencyDown.
+func (g *Graph) AdjacencyUp() map[string][]string {
+	return g.adjacency(upstreamOf)
+}
+
+// Returns every node mapped to a sorted copy of the relations selected
+// by next.
+func (g *Graph) adjacency(next func(*Node) []string) map[string][]string {
+	adjacency := make(map[string][]string, len(g.nodes))
+	for path, node := range g.nodes {
+		relations := append([]string{}, next(node)...)
+		sort.Strings(relations)
+		adjacency[path] = relations
+	}
+	return adjacency
+}
+
 // Print the graph nodes. Used for debugging.
 func (g *Graph) print() {
 	for _, node := range g.nodes {
@@ -155,44 +286,15 @@ func (g *Graph) print() {
 }
 
 // NewGraphFromParquet reads input parquet file and greates a graph from
-// the given relationships.
+// the given relationships. See LoadParquet for how rows are read.
 func NewGraphFromParquet(path string) (*Graph, error) {
-	skip, limit := 0, 1000
-	graph := &Graph{}
-	for {
-		records, err := ReadParquet(path, skip, limit)
-		if err != nil {
-			return nil, err
-		}
-		if len(records) == 0 {
-			break
-		}
-		for _, record := range records {
-			graph.insert(record.source, record.target)
-		}
-		skip += limit
-	}
-	return graph, nil
+	graph, _, err := LoadParquet(path, LoadOptions{})
+	return graph, err
 }
 
 // NewGraphFromCsv reads input CSV file and greates a graph from
-// the given relationships.
+// the given relationships. See LoadCsv for how rows are read.
 func NewGraphFromCsv(path string) (*Graph, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	csvReader := csv.NewReader(f)
-	records, err := csvReader.ReadAll()
-	if err != nil {
-		return nil, err
-	}
-
-	graph := &Graph{}
-	for _, record := range records[1:] {
-		graph.insert(record[0], record[1])
-	}
-	return graph, nil
+	graph, _, err := LoadCsv(path, LoadOptions{})
+	return graph, err
 }
@@ -9,6 +9,34 @@ import (
 	"time"
 )
 
+// jaffleShopGraph builds the jaffle_shop lineage graph used as a
+// fixture across tests.
+//
+//	jaffle_shop.customers -> stg_customers -> dim_customers
+//	jaffle_shop.orders -> stg_orders -> dim_customers, fct_orders
+//	stripe.payment -> stg_payments -> fct_orders
+//	dim_customers, fct_orders, gsheets.goals -> weekly_jaffle_metrics
+func jaffleShopGraph() *Graph {
+	nodes := map[string][]string{
+		"jaffle_shop.customers": []string{"stg_customers"},
+		"jaffle_shop.orders":    []string{"stg_orders"},
+		"stripe.payment":        []string{"stg_payments"},
+		"gsheets.goals":         []string{"weekly_jaffle_metrics"},
+		"stg_customers":         []string{"dim_customers"},
+		"stg_orders":            []string{"dim_customers", "fct_orders"},
+		"stg_payments":          []string{"fct_orders"},
+		"dim_customers":         []string{"weekly_jaffle_metrics"},
+		"fct_orders":            []string{"weekly_jaffle_metrics"},
+	}
+	graph := &Graph{}
+	for path, downstreams := range nodes {
+		for _, ds := range downstreams {
+			graph.insert(path, ds)
+		}
+	}
+	return graph
+}
+
 // TestInsert calls graph.insert to construct a graph and
 // checks the constructed graph for expected structure.
 func TestInsert(t *testing.T) {
@@ -100,20 +128,143 @@ func TestInsertWithDuplicateEntry(t *testing.T) {
 	}
 }
 
+// TestInsertionOrder inserts edges in a specific order and checks
+// that the ordered accessors preserve it.
+func TestInsertionOrder(t *testing.T) {
+	graph := &Graph{}
+	graph.insert("stg_payments", "fct_orders")
+	graph.insert("stg_orders", "fct_orders")
+	graph.insert("stg_customers", "fct_orders")
+	graph.insert("stg_payments", "fct_orders")
+	graph.insert("fct_orders", "weekly_jaffle_metrics")
+	graph.insert("fct_orders", "finance_report")
+
+	ordered, err := graph.UpstreamOrdered("fct_orders")
+	if err != nil {
+		t.Fatalf("Error getting upstream - %v", err)
+	}
+	upstream := strings.Join(ordered, ",")
+	expectedUpstream := "stg_payments,stg_orders,stg_customers"
+	if upstream != expectedUpstream {
+		t.Fatalf(`Upstream order mismatch. Expected %v, Found %v`, expectedUpstream, upstream)
+	}
+	ordered, err = graph.DownstreamOrdered("fct_orders")
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	downstream := strings.Join(ordered, ",")
+	expectedDownstream := "weekly_jaffle_metrics,finance_report"
+	if downstream != expectedDownstream {
+		t.Fatalf(`Downstream order mismatch. Expected %v, Found %v`, expectedDownstream, downstream)
+	}
+
+	// the returned slice is a copy
+	ordered, _ = graph.UpstreamOrdered("fct_orders")
+	ordered[0] = "changed"
+	if ordered, _ = graph.UpstreamOrdered("fct_orders"); ordered[0] != "stg_payments" {
+		t.Fatalf("UpstreamOrdered exposed the node's relations")
+	}
+
+	if _, err := graph.DownstreamOrdered("missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestInsertUpstream checks that inserting a dependency produces the
+// same structure as inserting the relation in lineage direction.
+func TestInsertUpstream(t *testing.T) {
+	graph := &Graph{}
+	graph.InsertUpstream("fct_orders", "stg_orders")
+
+	expected := &Graph{}
+	expected.insert("stg_orders", "fct_orders")
+
+	if equal, details := graph.Compare(expected); !equal {
+		t.Fatalf("Graph mismatch\n%s", details)
+	}
+	if !contains(graph.nodes["fct_orders"].upstream, "stg_orders") {
+		t.Fatalf("Expected upstream stg_orders for fct_orders")
+	}
+}
+
+// TestPublicAPI builds a graph with the exported constructor and
+// insert, and queries it with the exported traversals.
+func TestPublicAPI(t *testing.T) {
+	graph := NewGraph()
+	graph.Insert("jaffle_shop.orders", "stg_orders")
+	graph.Insert("stg_orders", "fct_orders")
+	graph.Insert("stg_payments", "fct_orders")
+
+	upstream, err := graph.Upstream([]string{"fct_orders"})
+	if err != nil {
+		t.Fatalf("Error getting upstream - %v", err)
+	}
+	sort.Strings(upstream)
+	expected := "jaffle_shop.orders,stg_orders,stg_payments"
+	if found := strings.Join(upstream, ","); found != expected {
+		t.Fatalf("Upstream mismatch. Expected %v, Found %v", expected, found)
+	}
+	downstream, err := graph.Downstream([]string{"jaffle_shop.orders"})
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	sort.Strings(downstream)
+	expected = "fct_orders,stg_orders"
+	if found := strings.Join(downstream, ","); found != expected {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", expected, found)
+	}
+	if _, err := graph.Downstream([]string{"missing"}); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestCompact checks that compacting removes spare capacity from the
+// relations without changing query results.
+func TestCompact(t *testing.T) {
+	graph := jaffleShopGraph()
+	graph.Compact()
+
+	for path, node := range graph.nodes {
+		if cap(node.upstream) != len(node.upstream) || cap(node.downstream) != len(node.downstream) {
+			t.Fatalf("Spare capacity left for %s", path)
+		}
+	}
+
+	downstream, err := graph.downstream([]string{"stg_orders"})
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	sort.Strings(downstream)
+	expected := "dim_customers,fct_orders,weekly_jaffle_metrics"
+	if strings.Join(downstream, ",") != expected {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", expected, downstream)
+	}
+}
+
 // TestParquet reads the parquet file and calls graph.insert to
 // construct the graph for every record. Checks the constructed graph
-// for expected structure.
-// func TestParquet(t *testing.T) {
-// 	filename := "synq-lineage.parquet"
-// 	graph, err := NewGraphFromParquet(filename)
-//  if err != nil {
-//    t.Fatalf("Unable to read input file %s - %v", filename, err)
-//  }
-// 	// assert number of nodes
-// 	if len(graph.nodes) != 266 {
-// 		t.Fatalf(`Node count mismatch. Expected %d, Found %d`, 266, len(graph.nodes))
-// 	}
-// }
+// for expected structure. The file is dictionary encoded with
+// optional columns.
+func TestParquet(t *testing.T) {
+	filename := "synq-lineage.parquet"
+	graph, err := NewGraphFromParquet(filename)
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+	// assert number of nodes
+	if len(graph.nodes) != 266 {
+		t.Fatalf(`Node count mismatch. Expected %d, Found %d`, 266, len(graph.nodes))
+	}
+
+	// assert the same graph as the CSV conversion
+	csvGraph, err := NewGraphFromCsv("synq-lineage.csv")
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", "synq-lineage.csv", err)
+	}
+	if equal, details := graph.Compare(csvGraph); !equal {
+		t.Fatalf("Graph mismatch with CSV input\n%s", details)
+	}
+}
 
 // TestCsv reads the CSV input file and calls graph.insert to
 // construct the graph for every record. Checks the constructed graph
@@ -140,6 +291,48 @@ func TestCsv(t *testing.T) {
 	}
 }
 
+// TestAdjacency asserts the sorted adjacency maps of the fixture and
+// that they are copies of the node relations.
+func TestAdjacency(t *testing.T) {
+	graph := jaffleShopGraph()
+	down := graph.AdjacencyDown()
+	if len(down) != 10 {
+		t.Fatalf("Adjacency size mismatch. Expected %d, Found %d", 10, len(down))
+	}
+	if result := strings.Join(down["stg_orders"], ","); result != "dim_customers,fct_orders" {
+		t.Fatalf("Downstream adjacency mismatch. Expected %v, Found %v", "dim_customers,fct_orders", result)
+	}
+	if len(down["weekly_jaffle_metrics"]) != 0 {
+		t.Fatalf("Downstream adjacency mismatch. Expected %v, Found %v", []string{}, down["weekly_jaffle_metrics"])
+	}
+	up := graph.AdjacencyUp()
+	expected := "dim_customers,fct_orders,gsheets.goals"
+	if result := strings.Join(up["weekly_jaffle_metrics"], ","); result != expected {
+		t.Fatalf("Upstream adjacency mismatch. Expected %v, Found %v", expected, result)
+	}
+
+	// modifying the map does not modify the graph
+	down["stg_orders"][0] = "changed"
+	if contains(graph.nodes["stg_orders"].downstream, "changed") {
+		t.Fatalf("Expected adjacency to be a copy")
+	}
+}
+
+// TestAreAdjacent asserts direct relations in both directions on the
+// jaffle_shop graph.
+func TestAreAdjacent(t *testing.T) {
+	graph := jaffleShopGraph()
+	if !graph.AreAdjacent("stg_orders", "fct_orders") || !graph.AreAdjacent("fct_orders", "stg_orders") {
+		t.Fatalf("Expected stg_orders and fct_orders to be adjacent")
+	}
+	if graph.AreAdjacent("stg_orders", "weekly_jaffle_metrics") {
+		t.Fatalf("Expected stg_orders and weekly_jaffle_metrics not to be adjacent")
+	}
+	if graph.AreAdjacent("missing", "stg_orders") {
+		t.Fatalf("Expected a missing node not to be adjacent")
+	}
+}
+
 // TestUpstream asserts correct upstream output for basic graph.
 func TestUpstream(t *testing.T) {
 	nodes := map[string][]string{
@@ -187,6 +380,40 @@ func TestUpstream(t *testing.T) {
 	}
 }
 
+// TestTraverseOverlappingSeeds passes a seed that is downstream of
+// another seed and asserts that every node is looked up only once and
+// the result is unchanged.
+func TestTraverseOverlappingSeeds(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	// Query: graph.downstream(stg_orders, fct_orders)
+	// Result: [dim_customers, fct_orders, weekly_jaffle_metrics]
+	downstream, lookups, err := graph.traverse([]string{"stg_orders", "fct_orders"}, downstreamOf, -1)
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	sort.Strings(downstream)
+	expected := []string{"dim_customers", "fct_orders", "weekly_jaffle_metrics"}
+	if strings.Join(downstream, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", expected, downstream)
+	}
+	// stg_orders, fct_orders, dim_customers and weekly_jaffle_metrics
+	// are each looked up once, fct_orders is not expanded again when
+	// it is reached from stg_orders
+	if lookups != 4 {
+		t.Fatalf("Lookup count mismatch. Expected %d, Found %d", 4, lookups)
+	}
+
+	// the same seed passed twice is looked up once
+	_, lookups, err = graph.traverse([]string{"fct_orders", "fct_orders"}, downstreamOf, -1)
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	if lookups != 2 {
+		t.Fatalf("Lookup count mismatch. Expected %d, Found %d", 2, lookups)
+	}
+}
+
 // TestDownstream asserts correct downstream output for basic graph.
 func TestDownstream(t *testing.T) {
 	nodes := map[string][]string{
@@ -247,6 +474,40 @@ func TestDownstream(t *testing.T) {
 	}
 }
 
+// TestTraverseDepth checks the depth-limited upstream and downstream
+// traversals on the jaffle_shop graph.
+func TestTraverseDepth(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	tests := []struct {
+		name     string
+		traverse func([]string, int) ([]string, error)
+		paths    []string
+		depth    int
+		expected string
+	}{
+		{"downstream immediate", graph.DownstreamN, []string{"jaffle_shop.orders"}, 0, "stg_orders"},
+		{"downstream one hop", graph.DownstreamN, []string{"jaffle_shop.orders"}, 1, "dim_customers,fct_orders,stg_orders"},
+		{"downstream unlimited", graph.DownstreamN, []string{"jaffle_shop.orders"}, -1, "dim_customers,fct_orders,stg_orders,weekly_jaffle_metrics"},
+		{"upstream immediate", graph.UpstreamN, []string{"weekly_jaffle_metrics"}, 0, "dim_customers,fct_orders,gsheets.goals"},
+		{"upstream one hop", graph.UpstreamN, []string{"fct_orders"}, 1, "jaffle_shop.orders,stg_orders,stg_payments,stripe.payment"},
+	}
+	for _, test := range tests {
+		result, err := test.traverse(test.paths, test.depth)
+		if err != nil {
+			t.Fatalf("Error traversing %s - %v", test.name, err)
+		}
+		sort.Strings(result)
+		if found := strings.Join(result, ","); found != test.expected {
+			t.Fatalf("Traversal mismatch for %s. Expected %v, Found %v", test.name, test.expected, found)
+		}
+	}
+
+	if _, err := graph.DownstreamN([]string{"missing"}, 1); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
 // TestLoad checks runtime for huge graphs
 func TestLoad(t *testing.T) {
 	graph := &Graph{}
@@ -0,0 +1,65 @@
+// Package graphtest provides helpers for testing code that builds
+// lineage graphs, such as new format loaders. Expected graphs are
+// described as adjacency maps from each node to its downstream nodes.
+package graphtest
+
+import (
+	"fmt"
+	"testing"
+
+	graph "grasskode/synq-graphs"
+)
+
+// fixtures are the canonical adjacency maps that can be loaded by
+// name with LoadFixture.
+var fixtures = map[string]map[string][]string{
+	// the jaffle_shop example project lineage
+	"jaffle_shop": {
+		"jaffle_shop.customers": {"stg_customers"},
+		"jaffle_shop.orders":    {"stg_orders"},
+		"stripe.payment":        {"stg_payments"},
+		"gsheets.goals":         {"weekly_jaffle_metrics"},
+		"stg_customers":         {"dim_customers"},
+		"stg_orders":            {"dim_customers", "fct_orders"},
+		"stg_payments":          {"fct_orders"},
+		"dim_customers":         {"weekly_jaffle_metrics"},
+		"fct_orders":            {"weekly_jaffle_metrics"},
+	},
+	// a short chain of orders models
+	"orders_chain": {
+		"jaffle_shop.orders": {"stg_orders"},
+		"stg_orders":         {"fct_orders"},
+	},
+}
+
+// FromAdjacency builds a graph from an adjacency map of each node to
+// its downstream nodes.
+func FromAdjacency(adjacency map[string][]string) *graph.Graph {
+	g := &graph.Graph{}
+	for path, downstreams := range adjacency {
+		for _, ds := range downstreams {
+			g.InsertUpstream(ds, path)
+		}
+	}
+	return g
+}
+
+// LoadFixture builds the graph of the named canonical fixture. It
+// panics if there is no fixture with the name.
+func LoadFixture(name string) *graph.Graph {
+	adjacency, ok := fixtures[name]
+	if !ok {
+		panic(fmt.Sprintf("graphtest: unknown fixture %s", name))
+	}
+	return FromAdjacency(adjacency)
+}
+
+// AssertGraphEqual fails the test if got does not have the same nodes
+// and edges as want. Nodes and edges only in got are reported with "-"
+// and those only in want with "+".
+func AssertGraphEqual(t testing.TB, got, want *graph.Graph) {
+	t.Helper()
+	if equal, details := got.Compare(want); !equal {
+		t.Fatalf("Graph mismatch.\n%s", details)
+	}
+}
@@ -0,0 +1,26 @@
+package graphtest
+
+import (
+	"testing"
+)
+
+// TestLoadFixture checks that a fixture is built from its adjacency
+// map and compares equal to itself.
+func TestLoadFixture(t *testing.T) {
+	graph := LoadFixture("jaffle_shop")
+	stats := graph.Stats()
+	if stats.Nodes != 10 || stats.Edges != 10 {
+		t.Fatalf("Fixture size mismatch. Expected %d nodes and %d edges, Found %d nodes and %d edges", 10, 10, stats.Nodes, stats.Edges)
+	}
+	AssertGraphEqual(t, graph, LoadFixture("jaffle_shop"))
+}
+
+// TestLoadFixtureUnknown checks that loading an unknown fixture panics.
+func TestLoadFixtureUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected panic for unknown fixture")
+		}
+	}()
+	LoadFixture("missing")
+}
@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// reachable returns the given paths and every node reachable from them
+// along the relations selected by next. Skipped nodes are neither
+// entered nor expanded, as if they were removed from the graph.
+func (g *Graph) reachable(paths []string, next func(*Node) []string, skip map[string]bool) map[string]bool {
+	found := make(map[string]bool)
+	for len(paths) > 0 {
+		path := paths[0]
+		paths = paths[1:]
+		if found[path] || skip[path] {
+			continue
+		}
+		node, ok := g.nodes[path]
+		if !ok {
+			continue
+		}
+		found[path] = true
+		paths = append(paths, next(node)...)
+	}
+	return found
+}
+
+// WhatIfRemove returns the nodes that would lose all connectivity to
+// the roots of the graph if the given path were removed. Descendants
+// that are still fed by another root through an alternate path are
+// not orphaned. The graph is not modified.
+func (g *Graph) WhatIfRemove(path string) (orphaned []string, err error) {
+	affected, err := g.downstream([]string{path})
+	if err != nil {
+		return nil, err
+	}
+
+	roots := []string{}
+	for p, node := range g.nodes {
+		if p != path && len(node.upstream) == 0 {
+			roots = append(roots, p)
+		}
+	}
+	connected := g.reachable(roots, downstreamOf, map[string]bool{path: true})
+
+	orphaned = []string{}
+	for _, a := range affected {
+		if !connected[a] {
+			orphaned = append(orphaned, a)
+		}
+	}
+	sort.Strings(orphaned)
+	return orphaned, nil
+}
+
+// DownstreamWithDisabled gets all the downstream nodes for the given
+// paths as if the disabled nodes were removed from the graph. Disabled
+// nodes are neither included nor expanded, so their descendants are
+// only included if another path reaches them. The result is sorted.
+func (g *Graph) DownstreamWithDisabled(paths []string, disabled map[string]bool) ([]string, error) {
+	children := []string{}
+	for _, path := range paths {
+		node, ok := g.nodes[path]
+		if !ok {
+			return nil, &MissingNodeError{path: path}
+		}
+		if !disabled[path] {
+			children = append(children, node.downstream...)
+		}
+	}
+
+	found := g.reachable(children, downstreamOf, disabled)
+	downstream := make([]string, 0, len(found))
+	for path := range found {
+		downstream = append(downstream, path)
+	}
+	sort.Strings(downstream)
+	return downstream, nil
+}
+
+// NewlyReachableAfterEdge returns the nodes that became reachable from
+// the from node when the edge to the to node was added, i.e. the to
+// node and its downstream nodes that are not also reachable from the
+// from node without the edge. The edge must already be in the graph.
+// The result is sorted.
+func (g *Graph) NewlyReachableAfterEdge(from, to string) ([]string, error) {
+	for _, path := range []string{from, to} {
+		if _, ok := g.nodes[path]; !ok {
+			return nil, &MissingNodeError{path: path}
+		}
+	}
+	if !g.hasEdge(from, to) {
+		return nil, fmt.Errorf("missing edge from %s to %s", from, to)
+	}
+
+	others := []string{}
+	for _, ds := range g.nodes[from].downstream {
+		if ds != to {
+			others = append(others, ds)
+		}
+	}
+	// from is skipped so that a cycle cannot lead back to the edge
+	skip := map[string]bool{from: true}
+	before := g.reachable(others, downstreamOf, skip)
+	after := g.reachable([]string{to}, downstreamOf, skip)
+
+	reachable := []string{}
+	for path := range after {
+		if !before[path] {
+			reachable = append(reachable, path)
+		}
+	}
+	sort.Strings(reachable)
+	return reachable, nil
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWhatIfRemove asserts that removing a sole connector orphans its
+// descendants while nodes with an alternate path stay connected.
+func TestWhatIfRemove(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	// Query: graph.WhatIfRemove(stg_customers)
+	// Result: [] since dim_customers is also fed by stg_orders
+	orphaned, err := graph.WhatIfRemove("stg_customers")
+	if err != nil {
+		t.Fatalf("Error getting orphaned nodes - %v", err)
+	}
+	if len(orphaned) != 0 {
+		t.Fatalf("Orphaned mismatch. Expected %v, Found %v", []string{}, orphaned)
+	}
+
+	// Query: graph.WhatIfRemove(stg_payments) on a graph where it is
+	// the only feed of a payments mart
+	graph.insert("stg_payments", "fct_payments")
+	graph.insert("fct_payments", "finance_report")
+	orphaned, err = graph.WhatIfRemove("stg_payments")
+	if err != nil {
+		t.Fatalf("Error getting orphaned nodes - %v", err)
+	}
+	expected := "fct_payments,finance_report"
+	if strings.Join(orphaned, ",") != expected {
+		t.Fatalf("Orphaned mismatch. Expected %v, Found %v", expected, orphaned)
+	}
+
+	// the graph is not modified
+	if _, ok := graph.nodes["stg_payments"]; !ok {
+		t.Fatalf("WhatIfRemove modified the graph")
+	}
+
+	if _, err := graph.WhatIfRemove("missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestDownstreamWithDisabled disables fct_orders and asserts that
+// weekly_jaffle_metrics stays reachable only through another path.
+func TestDownstreamWithDisabled(t *testing.T) {
+	graph := jaffleShopGraph()
+	disabled := map[string]bool{"fct_orders": true}
+
+	// Query: graph.DownstreamWithDisabled(jaffle_shop.orders)
+	// Result: weekly_jaffle_metrics is still fed through dim_customers
+	downstream, err := graph.DownstreamWithDisabled([]string{"jaffle_shop.orders"}, disabled)
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	expected := "dim_customers,stg_orders,weekly_jaffle_metrics"
+	if result := strings.Join(downstream, ","); result != expected {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", expected, result)
+	}
+
+	// Query: graph.DownstreamWithDisabled(stripe.payment)
+	// Result: weekly_jaffle_metrics is only fed through fct_orders
+	downstream, err = graph.DownstreamWithDisabled([]string{"stripe.payment"}, disabled)
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	if result := strings.Join(downstream, ","); result != "stg_payments" {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", "stg_payments", result)
+	}
+
+	if _, err := graph.DownstreamWithDisabled([]string{"missing"}, disabled); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestNewlyReachableAfterEdge adds edges to the jaffle_shop graph and
+// asserts that only the genuinely new reachable nodes are returned.
+func TestNewlyReachableAfterEdge(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	// a shortcut to a node that was already reachable adds nothing
+	graph.insert("stg_orders", "weekly_jaffle_metrics")
+	reachable, err := graph.NewlyReachableAfterEdge("stg_orders", "weekly_jaffle_metrics")
+	if err != nil {
+		t.Fatalf("Error getting newly reachable nodes - %v", err)
+	}
+	if len(reachable) != 0 {
+		t.Fatalf("Newly reachable mismatch. Expected %v, Found %v", []string{}, reachable)
+	}
+
+	// Query: graph.NewlyReachableAfterEdge(stg_customers, fct_orders)
+	// Result: fct_order_items, fct_orders, as weekly_jaffle_metrics
+	// was already reachable through dim_customers
+	graph.insert("stg_customers", "fct_orders")
+	graph.insert("fct_orders", "fct_order_items")
+	reachable, err = graph.NewlyReachableAfterEdge("stg_customers", "fct_orders")
+	if err != nil {
+		t.Fatalf("Error getting newly reachable nodes - %v", err)
+	}
+	expected := "fct_order_items,fct_orders"
+	if result := strings.Join(reachable, ","); result != expected {
+		t.Fatalf("Newly reachable mismatch. Expected %v, Found %v", expected, result)
+	}
+
+	if _, err := graph.NewlyReachableAfterEdge("stg_payments", "dim_customers"); err == nil {
+		t.Fatalf("Expected error for missing edge")
+	}
+	if _, err := graph.NewlyReachableAfterEdge("missing", "fct_orders"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONRecord holds a source and all of its targets in the JSON
+// record input.
+type JSONRecord struct {
+	Source  string   `json:"source"`
+	Targets []string `json:"targets"`
+}
+
+// NewGraphFromJSONRecords reads a JSON array of records of the form
+// {"source": "a", "targets": ["b", "c"]} and creates a graph with an
+// edge from each source to every one of its targets. A record with no
+// targets creates an isolated source node. Like NewGraphFromJSONLines,
+// a record without a source or with an empty target is an error.
+func NewGraphFromJSONRecords(r io.Reader) (*Graph, error) {
+	graph, _, err := LoadJSONRecords(r, LoadOptions{})
+	return graph, err
+}
+
+// LoadJSONRecords reads JSON records like NewGraphFromJSONRecords,
+// applying the load options to every relation. Each relation from a
+// source to one of its targets counts as a row.
+func LoadJSONRecords(r io.Reader, opts LoadOptions) (*Graph, LoadResult, error) {
+	result := LoadResult{}
+	var records []JSONRecord
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, result, err
+	}
+
+	graph := &Graph{}
+	for i, record := range records {
+		if record.Source == "" {
+			return nil, result, fmt.Errorf("record %d is missing a source", i+1)
+		}
+		graph.getOrCreate(record.Source)
+		for _, target := range record.Targets {
+			result.Rows++
+			if target == "" {
+				return nil, result, fmt.Errorf("record %d has an empty target", i+1)
+			}
+			if err := opts.insert(graph, record.Source, target, &result); err != nil {
+				return nil, result, err
+			}
+			opts.progress(result.Rows, false)
+		}
+	}
+	opts.progress(result.Rows, true)
+	return graph, result, nil
+}
+
+// JSONLine holds a single relation in the newline-delimited JSON
+// input.
+type JSONLine struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// NewGraphFromJSONLines reads a stream of objects of the form
+// {"source": "a", "target": "b"}, usually one per line, and creates a
+// graph with an edge for every object. An object without a source or
+// target is an error.
+func NewGraphFromJSONLines(r io.Reader) (*Graph, error) {
+	graph, _, err := LoadJSONLines(r, LoadOptions{})
+	return graph, err
+}
+
+// LoadJSONLines reads JSON lines like NewGraphFromJSONLines, applying
+// the load options to every object.
+func LoadJSONLines(r io.Reader, opts LoadOptions) (*Graph, LoadResult, error) {
+	result := LoadResult{}
+	decoder := json.NewDecoder(r)
+	graph := &Graph{}
+	for {
+		var record JSONLine
+		if err := decoder.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, result, err
+		}
+		result.Rows++
+		if record.Source == "" || record.Target == "" {
+			return nil, result, fmt.Errorf("record %d is missing a source or target", result.Rows)
+		}
+		if err := opts.insert(graph, record.Source, record.Target, &result); err != nil {
+			return nil, result, err
+		}
+		opts.progress(result.Rows, false)
+	}
+	opts.progress(result.Rows, true)
+	return graph, result, nil
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestJSONRecords reads an embedded JSON array of records and checks
+// the fan-out of the constructed graph.
+func TestJSONRecords(t *testing.T) {
+	input := `[
+		{"source": "stg_orders", "targets": ["dim_customers", "fct_orders"]},
+		{"source": "stg_payments", "targets": ["fct_orders"]},
+		{"source": "stg_orders", "targets": ["fct_orders"]},
+		{"source": "gsheets.goals", "targets": []}
+	]`
+	graph, err := NewGraphFromJSONRecords(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unable to read JSON records - %v", err)
+	}
+
+	// assert number of nodes
+	if len(graph.nodes) != 5 {
+		t.Fatalf(`Node count mismatch. Expected %d, Found %d`, 5, len(graph.nodes))
+	}
+
+	// assert fan-out of stg_orders with the duplicate ignored
+	node := graph.nodes["stg_orders"]
+	sort.Strings(node.downstream)
+	downstream := strings.Join(node.downstream, ",")
+	expectedDownstream := "dim_customers,fct_orders"
+	if downstream != expectedDownstream {
+		t.Fatalf(`Downstream relations mismatch. Expected %v, Found %v`, expectedDownstream, downstream)
+	}
+
+	// assert fan-in of fct_orders
+	node = graph.nodes["fct_orders"]
+	sort.Strings(node.upstream)
+	upstream := strings.Join(node.upstream, ",")
+	expectedUpstream := "stg_orders,stg_payments"
+	if upstream != expectedUpstream {
+		t.Fatalf(`Upstream relations mismatch. Expected %v, Found %v`, expectedUpstream, upstream)
+	}
+
+	// assert the source without targets is isolated
+	node, ok := graph.nodes["gsheets.goals"]
+	if !ok || len(node.upstream) != 0 || len(node.downstream) != 0 {
+		t.Fatalf("Expected isolated node gsheets.goals")
+	}
+
+	// malformed input is an error
+	if _, err := NewGraphFromJSONRecords(strings.NewReader(`{"source": "a"}`)); err == nil {
+		t.Fatalf("Expected error for malformed input")
+	}
+	// an empty source or target is an error rather than a "" node
+	if _, err := NewGraphFromJSONRecords(strings.NewReader(`[{"targets": ["fct_orders"]}]`)); err == nil {
+		t.Fatalf("Expected error for missing source")
+	}
+	if _, err := NewGraphFromJSONRecords(strings.NewReader(`[{"source": "stg_orders", "targets": ["fct_orders", ""]}]`)); err == nil {
+		t.Fatalf("Expected error for empty target")
+	}
+}
+
+// TestJSONLines reads newline-delimited relations and checks that an
+// object without a target is rejected.
+func TestJSONLines(t *testing.T) {
+	input := `{"source": "stg_orders", "target": "fct_orders"}
+{"source": "stg_payments", "target": "fct_orders"}
+{"source": "stg_orders", "target": "fct_orders"}
+`
+	graph, err := NewGraphFromJSONLines(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unable to read JSON lines - %v", err)
+	}
+	expected := "stg_orders->fct_orders,stg_payments->fct_orders"
+	if edges := edgeList(graph); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+
+	if _, err := NewGraphFromJSONLines(strings.NewReader(`{"source": "stg_orders"}`)); err == nil {
+		t.Fatalf("Expected error for missing target")
+	}
+}
@@ -0,0 +1,73 @@
+package graph
+
+import "sort"
+
+// SetNodeKind sets the kind of the node, e.g. "source", "seed" or
+// "model". Nodes have no kind until one is set.
+func (g *Graph) SetNodeKind(path, kind string) error {
+	node, ok := g.nodes[path]
+	if !ok {
+		return &MissingNodeError{path: path}
+	}
+	node.kind = kind
+	return nil
+}
+
+// NodeKind returns the kind of the node, or an empty string if it has
+// none.
+func (g *Graph) NodeKind(path string) (string, error) {
+	node, ok := g.nodes[path]
+	if !ok {
+		return "", &MissingNodeError{path: path}
+	}
+	return node.kind, nil
+}
+
+// DownstreamOfKind gets all the downstream nodes of the given paths
+// whose kind is one of the given kinds. Nodes of other kinds are still
+// traversed, so their descendants of an allowed kind are included. The
+// result is sorted.
+func (g *Graph) DownstreamOfKind(paths []string, kinds ...string) ([]string, error) {
+	downstream, err := g.downstream(paths)
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	for _, path := range downstream {
+		if contains(kinds, g.nodes[path].kind) {
+			result = append(result, path)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// DownstreamWithinKind gets the downstream nodes of the given paths
+// that are reachable through nodes of the given kinds only. Unlike
+// DownstreamOfKind, a node of another kind prunes the traversal, so
+// its descendants are only included if another path reaches them. The
+// result is sorted.
+func (g *Graph) DownstreamWithinKind(paths []string, kinds ...string) ([]string, error) {
+	children := []string{}
+	for _, path := range paths {
+		node, ok := g.nodes[path]
+		if !ok {
+			return nil, &MissingNodeError{path: path}
+		}
+		children = append(children, node.downstream...)
+	}
+	excluded := make(map[string]bool)
+	for path, node := range g.nodes {
+		if !contains(kinds, node.kind) {
+			excluded[path] = true
+		}
+	}
+
+	found := g.reachable(children, downstreamOf, excluded)
+	result := make([]string, 0, len(found))
+	for path := range found {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDownstreamOfKind tags the jaffle_shop nodes with kinds and checks
+// that the kind-filtered downstream excludes the sources.
+func TestDownstreamOfKind(t *testing.T) {
+	graph := jaffleShopGraph()
+	graph.insert("raw.refunds", "stg_refunds")
+	graph.insert("stg_refunds", "fct_orders")
+	graph.insert("stg_orders", "raw.refunds")
+	for path, kind := range map[string]string{
+		"jaffle_shop.orders":    "source",
+		"raw.refunds":           "source",
+		"stg_orders":            "model",
+		"stg_refunds":           "model",
+		"dim_customers":         "model",
+		"fct_orders":            "model",
+		"weekly_jaffle_metrics": "model",
+	} {
+		if err := graph.SetNodeKind(path, kind); err != nil {
+			t.Fatalf("Error setting kind - %v", err)
+		}
+	}
+
+	downstream, err := graph.DownstreamOfKind([]string{"jaffle_shop.orders"}, "model")
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	expected := "dim_customers,fct_orders,stg_orders,stg_refunds,weekly_jaffle_metrics"
+	if found := strings.Join(downstream, ","); found != expected {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", expected, found)
+	}
+
+	// pruning at the source raw.refunds drops stg_refunds
+	downstream, err = graph.DownstreamWithinKind([]string{"jaffle_shop.orders"}, "model")
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	expected = "dim_customers,fct_orders,stg_orders,weekly_jaffle_metrics"
+	if found := strings.Join(downstream, ","); found != expected {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", expected, found)
+	}
+
+	if kind, _ := graph.NodeKind("stripe.payment"); kind != "" {
+		t.Fatalf("Kind mismatch. Expected %v, Found %v", "", kind)
+	}
+	if _, err := graph.DownstreamOfKind([]string{"missing"}, "model"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+	if err := graph.SetNodeKind("missing", "model"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
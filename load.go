@@ -0,0 +1,121 @@
+package graph
+
+import "fmt"
+
+// NodeLimitError is thrown when loading an input would create more
+// nodes than allowed by LoadOptions.MaxNodes.
+type NodeLimitError struct {
+	limit int
+	row   int
+}
+
+func (n *NodeLimitError) Error() string {
+	return fmt.Sprintf("node limit of %d exceeded at row %d", n.limit, n.row)
+}
+
+// LoadResult reports how the rows of an input file were loaded into
+// a graph.
+type LoadResult struct {
+	// Rows is the number of data rows read from the input.
+	Rows int
+	// Skipped is the number of rows that were not inserted because
+	// the source or target was empty.
+	Skipped int
+	// Dropped is the number of rows whose edge was dropped by the
+	// EdgeTransform load option.
+	Dropped int
+}
+
+// Logger receives the diagnostics of a load, such as rows that were
+// skipped. A *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// defaultProgressInterval is the number of rows between progress
+// calls when LoadOptions does not set one.
+const defaultProgressInterval = 10000
+
+// LoadOptions configures how a graph is loaded from an input.
+// Every loader, LoadCsv, LoadParquet, LoadJSONRecords, LoadJSONLines,
+// LoadTSVStream, LoadArrow, LoadDir and LoadReaderAutodetect, applies
+// them the same way; SourceColName and TargetColName only apply to
+// CSV input. The zero value loads the whole input with no callbacks.
+type LoadOptions struct {
+	// OnProgress, if set, is called with the number of data rows
+	// read so far every ProgressInterval rows and once more with
+	// the final count when the input is exhausted.
+	OnProgress func(rowsRead int)
+	// ProgressInterval is the number of rows between progress
+	// calls. Defaults to defaultProgressInterval.
+	ProgressInterval int
+	// MaxNodes, if positive, aborts the load with a NodeLimitError
+	// as soon as the graph has more nodes than the limit.
+	MaxNodes int
+	// EdgeTransform, if set, is applied to every edge before it is
+	// inserted. It returns the paths to insert instead, and false to
+	// drop the edge.
+	EdgeTransform func(from, to string) (string, string, bool)
+	// SourceColName and TargetColName, if set, pick the source and
+	// target columns of a CSV input by their header name instead of
+	// by position. An unset name keeps the first column as the
+	// source and the second as the target.
+	SourceColName string
+	TargetColName string
+	// Origin, if set, is recorded on every inserted edge as the system
+	// it was loaded from. See Graph.EdgesFromSource.
+	Origin string
+	// Logger, if set, receives the load diagnostics. Diagnostics are
+	// discarded by default.
+	Logger Logger
+}
+
+// Logs a diagnostic if a logger is set.
+func (o *LoadOptions) logf(format string, v ...interface{}) {
+	if o.Logger != nil {
+		o.Logger.Printf(format, v...)
+	}
+}
+
+// Inserts the relation read from the last row of the result into the
+// graph, applying the edge transform and checking the node limit.
+func (o *LoadOptions) insert(graph *Graph, from, to string, result *LoadResult) error {
+	if o.EdgeTransform != nil {
+		var keep bool
+		if from, to, keep = o.EdgeTransform(from, to); !keep {
+			result.Dropped++
+			return nil
+		}
+	}
+	if o.Origin != "" {
+		graph.InsertOrigin(from, to, o.Origin)
+	} else {
+		graph.insert(from, to)
+	}
+	if o.MaxNodes > 0 && len(graph.nodes) > o.MaxNodes {
+		return &NodeLimitError{limit: o.MaxNodes, row: result.Rows}
+	}
+	return nil
+}
+
+// Reports the number of rows read if a progress callback is set and
+// the interval is reached, or unconditionally if final is true and
+// the count was not reported yet.
+func (o *LoadOptions) progress(rowsRead int, final bool) {
+	if o.OnProgress == nil {
+		return
+	}
+	interval := o.ProgressInterval
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+	if rowsRead%interval == 0 {
+		if !final {
+			o.OnProgress(rowsRead)
+		}
+		return
+	}
+	if final {
+		o.OnProgress(rowsRead)
+	}
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoadEdgeTransform loads the same edges from every stream format
+// and checks that the edge transform is applied by each loader.
+func TestLoadEdgeTransform(t *testing.T) {
+	// lowercase all paths and drop edges into the marketing namespace
+	transform := func(from, to string) (string, string, bool) {
+		from, to = strings.ToLower(from), strings.ToLower(to)
+		return from, to, !strings.HasPrefix(to, "marketing.")
+	}
+	opts := LoadOptions{EdgeTransform: transform}
+	inputs := []struct {
+		format string
+		load   func() (*Graph, LoadResult, error)
+	}{
+		{"json records", func() (*Graph, LoadResult, error) {
+			return LoadJSONRecords(strings.NewReader(`[{"source": "Jaffle_Shop.Orders", "targets": ["STG_ORDERS"]},
+				{"source": "stg_orders", "targets": ["marketing.campaigns", "fct_orders"]}]`), opts)
+		}},
+		{"json lines", func() (*Graph, LoadResult, error) {
+			return LoadJSONLines(strings.NewReader(`{"source": "Jaffle_Shop.Orders", "target": "STG_ORDERS"}
+				{"source": "stg_orders", "target": "marketing.campaigns"}
+				{"source": "stg_orders", "target": "fct_orders"}`), opts)
+		}},
+		{"tsv", func() (*Graph, LoadResult, error) {
+			return LoadTSVStream(strings.NewReader("Jaffle_Shop.Orders\tSTG_ORDERS\nstg_orders\tmarketing.campaigns\nstg_orders\tfct_orders\n"), opts)
+		}},
+		{"autodetected csv", func() (*Graph, LoadResult, error) {
+			return LoadReaderAutodetect(strings.NewReader("source,target\nJaffle_Shop.Orders,STG_ORDERS\nstg_orders,marketing.campaigns\nstg_orders,fct_orders\n"), opts)
+		}},
+	}
+	expected := "jaffle_shop.orders->stg_orders,stg_orders->fct_orders"
+	for _, input := range inputs {
+		graph, result, err := input.load()
+		if err != nil {
+			t.Fatalf("Unable to read %s input - %v", input.format, err)
+		}
+		if result.Rows != 3 || result.Dropped != 1 {
+			t.Fatalf("Load result mismatch for %s input. Expected %v, Found %v", input.format, LoadResult{Rows: 3, Dropped: 1}, result)
+		}
+		if edges := edgeList(graph); edges != expected {
+			t.Fatalf("Edge mismatch for %s input. Expected %v, Found %v", input.format, expected, edges)
+		}
+	}
+}
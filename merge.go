@@ -0,0 +1,58 @@
+package graph
+
+import "sort"
+
+// Checks if both attributes hold the same kinds, regardless of their
+// order, the same validity interval and the same cost. Origins are not
+// compared.
+func (a *EdgeAttrs) equal(b *EdgeAttrs) bool {
+	if len(a.Kinds) != len(b.Kinds) || !a.ValidFrom.Equal(b.ValidFrom) || !a.ValidTo.Equal(b.ValidTo) || a.Cost != b.Cost {
+		return false
+	}
+	kindsA := append([]string{}, a.Kinds...)
+	kindsB := append([]string{}, b.Kinds...)
+	sort.Strings(kindsA)
+	sort.Strings(kindsB)
+	for i := range kindsA {
+		if kindsA[i] != kindsB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge inserts the nodes and edges of the other graph into the graph.
+// Edge attributes only present in the other graph are copied. When
+// both graphs have attributes for the same edge and they differ,
+// resolve is called with the graph's attributes as a and the other
+// graph's as b, and its result is kept. A nil resolve keeps the
+// graph's attributes. The origins of an edge are never resolved: the
+// merged edge records the origins from both graphs.
+func (g *Graph) Merge(other *Graph, resolve func(from, to string, a, b EdgeAttrs) EdgeAttrs) {
+	for _, path := range other.sortedPaths() {
+		g.getOrCreate(path)
+	}
+	for _, edge := range other.edges() {
+		g.insert(edge.From, edge.To)
+		theirs, ok := other.attrs[edge]
+		if !ok {
+			continue
+		}
+		ours, ok := g.attrs[edge]
+		if !ok {
+			g.copyAttrs(other, edge)
+			continue
+		}
+		origins := append([]string{}, ours.Origins...)
+		if resolve != nil && !ours.equal(theirs) {
+			resolved := resolve(edge.From, edge.To, *ours.clone(), *theirs.clone())
+			*ours = *resolved.clone()
+		}
+		for _, origin := range theirs.Origins {
+			if !contains(origins, origin) {
+				origins = append(origins, origin)
+			}
+		}
+		ours.Origins = origins
+	}
+}
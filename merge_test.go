@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMerge merges two graphs with conflicting edge attributes and
+// checks that the resolver decides the kept attributes.
+func TestMerge(t *testing.T) {
+	build := func() (*Graph, *Graph) {
+		graph := &Graph{}
+		graph.InsertKind("stg_orders", "fct_orders", "reads")
+		graph.InsertKind("stg_payments", "fct_orders", "reads")
+		other := &Graph{}
+		other.InsertKind("stg_orders", "fct_orders", "writes")
+		other.InsertKind("stg_payments", "fct_orders", "reads")
+		other.InsertKind("fct_orders", "weekly_jaffle_metrics", "reads")
+		other.getOrCreate("raw.events")
+		return graph, other
+	}
+
+	calls := 0
+	graph, other := build()
+	graph.Merge(other, func(from, to string, a, b EdgeAttrs) EdgeAttrs {
+		calls++
+		return b
+	})
+	// only the differing edge is resolved
+	if calls != 1 {
+		t.Fatalf("Resolver calls mismatch. Expected %d, Found %d", 1, calls)
+	}
+	if kinds := strings.Join(graph.attrs[Edge{From: "stg_orders", To: "fct_orders"}].Kinds, ","); kinds != "writes" {
+		t.Fatalf("Kinds mismatch. Expected %v, Found %v", "writes", kinds)
+	}
+	if kinds := strings.Join(graph.attrs[Edge{From: "fct_orders", To: "weekly_jaffle_metrics"}].Kinds, ","); kinds != "reads" {
+		t.Fatalf("Kinds mismatch. Expected %v, Found %v", "reads", kinds)
+	}
+	expected := "fct_orders->weekly_jaffle_metrics,stg_orders->fct_orders,stg_payments->fct_orders"
+	if edges := edgeList(graph); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+	if _, ok := graph.nodes["raw.events"]; !ok {
+		t.Fatalf("Expected isolated node raw.events to be merged")
+	}
+
+	// without a resolver the graph's attributes are kept
+	graph, other = build()
+	graph.Merge(other, nil)
+	if kinds := strings.Join(graph.attrs[Edge{From: "stg_orders", To: "fct_orders"}].Kinds, ","); kinds != "reads" {
+		t.Fatalf("Kinds mismatch. Expected %v, Found %v", "reads", kinds)
+	}
+}
+
+// TestMergeEdgesFromSource merges two graphs labeled with their origin
+// and checks that every source only reports its own edges.
+func TestMergeEdgesFromSource(t *testing.T) {
+	graph := &Graph{}
+	graph.InsertOrigin("stg_orders", "fct_orders", "dbt")
+	graph.InsertOrigin("stg_payments", "fct_orders", "dbt")
+	other := &Graph{}
+	other.InsertOrigin("stg_orders", "fct_orders", "airflow")
+	other.InsertOrigin("fct_orders", "weekly_jaffle_metrics", "airflow")
+	graph.Merge(other, nil)
+
+	format := func(edges []Edge) string {
+		list := []string{}
+		for _, edge := range edges {
+			list = append(list, edge.From+"->"+edge.To)
+		}
+		return strings.Join(list, ",")
+	}
+	expected := "stg_orders->fct_orders,stg_payments->fct_orders"
+	if edges := format(graph.EdgesFromSource("dbt")); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+	expected = "fct_orders->weekly_jaffle_metrics,stg_orders->fct_orders"
+	if edges := format(graph.EdgesFromSource("airflow")); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+	if edges := graph.EdgesFromSource("looker"); len(edges) != 0 {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", "[]", edges)
+	}
+}
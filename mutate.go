@@ -0,0 +1,110 @@
+package graph
+
+import "fmt"
+
+// Removes the first occurrence of a string from the slice, keeping
+// the order of the remaining strings.
+func remove(s []string, str string) []string {
+	for i, v := range s {
+		if v == str {
+			return append(s[:i], s[i+1:]...)
+		}
+	}
+	return s
+}
+
+// Removes the relation between the given paths, along with its
+// attributes. Both nodes are kept.
+func (g *Graph) removeEdge(from, to string) {
+	if node, ok := g.nodes[from]; ok {
+		node.downstream = remove(node.downstream, to)
+	}
+	if node, ok := g.nodes[to]; ok {
+		node.upstream = remove(node.upstream, from)
+	}
+	delete(g.attrs, Edge{From: from, To: to})
+}
+
+// Removes the node and all of its relations from the graph.
+func (g *Graph) removeNode(path string) {
+	node, ok := g.nodes[path]
+	if !ok {
+		return
+	}
+	for _, up := range append([]string{}, node.upstream...) {
+		g.removeEdge(up, path)
+	}
+	for _, ds := range append([]string{}, node.downstream...) {
+		g.removeEdge(path, ds)
+	}
+	delete(g.nodes, path)
+}
+
+// SpliceOut removes the node from the graph and connects each of its
+// upstream nodes directly to each of its downstream nodes, so that
+// reachability through the node is preserved.
+func (g *Graph) SpliceOut(path string) error {
+	node, ok := g.nodes[path]
+	if !ok {
+		return &MissingNodeError{path: path}
+	}
+	upstream := append([]string{}, node.upstream...)
+	downstream := append([]string{}, node.downstream...)
+	g.removeNode(path)
+	for _, up := range upstream {
+		for _, ds := range downstream {
+			g.insert(up, ds)
+		}
+	}
+	return nil
+}
+
+// ApplyChange applies a single changelog entry to the graph. The op
+// "add" inserts the relation and "remove" removes it, keeping both
+// nodes. Removing a relation between unknown nodes returns a
+// MissingNodeError, and removing an absent relation is a no-op so that
+// a changelog can be replayed.
+func (g *Graph) ApplyChange(op string, from, to string) error {
+	switch op {
+	case "add":
+		g.insert(from, to)
+	case "remove":
+		for _, path := range []string{from, to} {
+			if _, ok := g.nodes[path]; !ok {
+				return &MissingNodeError{path: path}
+			}
+		}
+		g.removeEdge(from, to)
+	default:
+		return fmt.Errorf("unknown change op %q", op)
+	}
+	return nil
+}
+
+// ReplaceSubgraph removes the nodes strictly between the boundary
+// nodes, i.e. those downstream of one boundary node and upstream of
+// another, and inserts the nodes and edges of the replacement in their
+// place. Boundary nodes are kept along with their edges to nodes
+// outside the region, so the replacement reconnects through the
+// boundary nodes it shares with the graph.
+func (g *Graph) ReplaceSubgraph(boundary []string, replacement *Graph) error {
+	downstream, err := g.downstream(boundary)
+	if err != nil {
+		return err
+	}
+	upstream, err := g.upstream(boundary)
+	if err != nil {
+		return err
+	}
+	between := make(map[string]bool, len(upstream))
+	for _, up := range upstream {
+		between[up] = true
+	}
+	for _, ds := range downstream {
+		if between[ds] && !contains(boundary, ds) {
+			g.removeNode(ds)
+		}
+	}
+	g.Merge(replacement, nil)
+	return nil
+}
@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestSpliceOut splices dim_customers out of the jaffle_shop graph and
+// checks that reachability is unchanged.
+func TestSpliceOut(t *testing.T) {
+	graph := jaffleShopGraph()
+	before, err := graph.downstream([]string{"jaffle_shop.customers"})
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+
+	if err := graph.SpliceOut("dim_customers"); err != nil {
+		t.Fatalf("Error splicing out node - %v", err)
+	}
+	if _, ok := graph.nodes["dim_customers"]; ok {
+		t.Fatalf("Expected dim_customers to be removed")
+	}
+	if !contains(graph.nodes["stg_customers"].downstream, "weekly_jaffle_metrics") {
+		t.Fatalf("Expected edge stg_customers->weekly_jaffle_metrics")
+	}
+	if !contains(graph.nodes["weekly_jaffle_metrics"].upstream, "stg_customers") {
+		t.Fatalf("Expected upstream stg_customers for weekly_jaffle_metrics")
+	}
+
+	// reachability is unchanged apart from the removed node
+	after, err := graph.downstream([]string{"jaffle_shop.customers"})
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	before = remove(before, "dim_customers")
+	sort.Strings(before)
+	sort.Strings(after)
+	if strings.Join(before, ",") != strings.Join(after, ",") {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", before, after)
+	}
+
+	var missingErr *MissingNodeError
+	if err := graph.SpliceOut("dim_customers"); !errors.As(err, &missingErr) {
+		t.Fatalf("Expected missing node error, Found %v", err)
+	}
+}
+
+// TestApplyChange replays a changelog of add and remove operations and
+// checks the final structure.
+func TestApplyChange(t *testing.T) {
+	graph := &Graph{}
+	changes := [][3]string{
+		{"add", "stg_orders", "fct_orders"},
+		{"add", "stg_payments", "fct_orders"},
+		{"add", "fct_orders", "weekly_jaffle_metrics"},
+		{"remove", "stg_payments", "fct_orders"},
+		{"add", "stg_payments", "fct_payments"},
+		{"remove", "stg_payments", "fct_orders"},
+	}
+	for _, change := range changes {
+		if err := graph.ApplyChange(change[0], change[1], change[2]); err != nil {
+			t.Fatalf("Error applying change %v - %v", change, err)
+		}
+	}
+
+	expected := "fct_orders->weekly_jaffle_metrics,stg_orders->fct_orders,stg_payments->fct_payments"
+	if edges := edgeList(graph); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+	if upstream := strings.Join(graph.nodes["fct_orders"].upstream, ","); upstream != "stg_orders" {
+		t.Fatalf("Upstream relations mismatch. Expected %v, Found %v", "stg_orders", upstream)
+	}
+
+	if err := graph.ApplyChange("remove", "missing", "fct_orders"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+	if err := graph.ApplyChange("rename", "stg_orders", "fct_orders"); err == nil {
+		t.Fatalf("Expected error for unknown op")
+	}
+}
+
+// TestReplaceSubgraph replaces the stg_* region of the jaffle_shop
+// graph and asserts that the boundary nodes reconnect to the
+// replacement.
+func TestReplaceSubgraph(t *testing.T) {
+	graph := jaffleShopGraph()
+	boundary := []string{"jaffle_shop.customers", "jaffle_shop.orders", "stripe.payment", "dim_customers", "fct_orders"}
+	replacement := &Graph{}
+	replacement.insert("jaffle_shop.customers", "stg_customers_v2")
+	replacement.insert("jaffle_shop.orders", "stg_orders_v2")
+	replacement.insert("stripe.payment", "stg_orders_v2")
+	replacement.insert("stg_customers_v2", "dim_customers")
+	replacement.insert("stg_orders_v2", "dim_customers")
+	replacement.insert("stg_orders_v2", "fct_orders")
+
+	if err := graph.ReplaceSubgraph(boundary, replacement); err != nil {
+		t.Fatalf("Error replacing subgraph - %v", err)
+	}
+	expected := "dim_customers->weekly_jaffle_metrics," +
+		"fct_orders->weekly_jaffle_metrics," +
+		"gsheets.goals->weekly_jaffle_metrics," +
+		"jaffle_shop.customers->stg_customers_v2," +
+		"jaffle_shop.orders->stg_orders_v2," +
+		"stg_customers_v2->dim_customers," +
+		"stg_orders_v2->dim_customers," +
+		"stg_orders_v2->fct_orders," +
+		"stripe.payment->stg_orders_v2"
+	if edges := edgeList(graph); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+	for _, path := range []string{"stg_customers", "stg_orders", "stg_payments"} {
+		if _, ok := graph.nodes[path]; ok {
+			t.Fatalf("Expected %s to be removed", path)
+		}
+	}
+
+	if err := graph.ReplaceSubgraph([]string{"missing"}, replacement); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
@@ -2,36 +2,186 @@ package graph
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/parquet"
 	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
 )
 
 // ParquetRecord holds a single record for graph input.
 type ParquetRecord struct {
-	source string `parquet:"name=source, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	target string `parquet:"name=target, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Source string
+	Target string
 }
 
-// ReadParquet expects a parquet filename and returns the graph records
-// in the file.
-func ReadParquet(filename string, skip int, limit int) ([]*ParquetRecord, error) {
+// parquetColumns reads the source and target columns of a parquet
+// file. The columns are looked up by name and read value by value, so
+// any page encoding (PLAIN, PLAIN_DICTIONARY, RLE_DICTIONARY, ...) and
+// both required and optional columns are supported. Null values are
+// read as empty strings.
+type parquetColumns struct {
+	file   source.ParquetFile
+	reader *reader.ParquetReader
+	source int64
+	target int64
+}
+
+// Opens the parquet file and finds its source and target columns.
+func openParquetColumns(filename string) (*parquetColumns, error) {
 	fr, err := local.NewLocalFileReader(filename)
 	if err != nil {
-		fmt.Println("Error reading file.")
 		return nil, err
 	}
-	pr, err := reader.NewParquetReader(fr, new(ParquetRecord), int64(limit))
+	return newParquetColumns(fr)
+}
+
+// Finds the source and target columns of the parquet file. The file is
+// closed if they cannot be found.
+func newParquetColumns(fr source.ParquetFile) (*parquetColumns, error) {
+	pr, err := reader.NewParquetColumnReader(fr, 1)
 	if err != nil {
-		fmt.Println("Error creating NewParquetReader.")
+		fr.Close()
+		return nil, err
+	}
+	columns := &parquetColumns{file: fr, reader: pr}
+	if columns.source, err = columns.index("source"); err != nil {
+		columns.close()
 		return nil, err
 	}
-	records := make([]*ParquetRecord, limit)
-	if err = pr.Read(&records); err != nil {
-		fmt.Println("Error reading records.")
+	if columns.target, err = columns.index("target"); err != nil {
+		columns.close()
 		return nil, err
 	}
-	pr.ReadStop()
-	fr.Close()
+	return columns, nil
+}
+
+// Returns the index of the string column with the given name, ignoring
+// case.
+func (p *parquetColumns) index(name string) (int64, error) {
+	handler := p.reader.SchemaHandler
+	for i, inPath := range handler.ValueColumns {
+		exPath := strings.Split(handler.InPathToExPath[inPath], "\x01")
+		if !strings.EqualFold(exPath[len(exPath)-1], name) {
+			continue
+		}
+		element := handler.SchemaElements[handler.MapIndex[inPath]]
+		if element.GetType() != parquet.Type_BYTE_ARRAY {
+			return 0, fmt.Errorf("column %s has type %s, expected BYTE_ARRAY", name, element.GetType())
+		}
+		return int64(i), nil
+	}
+	return 0, fmt.Errorf("missing column %s", name)
+}
+
+// Returns the number of rows in the file.
+func (p *parquetColumns) rows() int {
+	return int(p.reader.GetNumRows())
+}
+
+// Skips the next rows of both columns.
+func (p *parquetColumns) skip(rows int) {
+	p.reader.SkipRowsByIndex(p.source, int64(rows))
+	p.reader.SkipRowsByIndex(p.target, int64(rows))
+}
+
+// Reads up to limit records from the current row.
+func (p *parquetColumns) read(limit int) ([]*ParquetRecord, error) {
+	sources, _, _, err := p.reader.ReadColumnByIndex(p.source, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	targets, _, _, err := p.reader.ReadColumnByIndex(p.target, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) != len(targets) {
+		return nil, fmt.Errorf("column length mismatch, %d sources and %d targets", len(sources), len(targets))
+	}
+	records := make([]*ParquetRecord, len(sources))
+	for i := range sources {
+		records[i] = &ParquetRecord{Source: parquetString(sources[i]), Target: parquetString(targets[i])}
+	}
 	return records, nil
 }
+
+// Closes the file.
+func (p *parquetColumns) close() {
+	p.reader.ReadStop()
+	p.file.Close()
+}
+
+// Returns the string held by a value read from a BYTE_ARRAY column.
+func parquetString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	}
+	return ""
+}
+
+// ReadParquet expects a parquet filename and returns up to limit graph
+// records in the file, starting after the first skip records.
+func ReadParquet(filename string, skip int, limit int) ([]*ParquetRecord, error) {
+	columns, err := openParquetColumns(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading file %s: %w", filename, err)
+	}
+	defer columns.close()
+	if skip >= columns.rows() {
+		return []*ParquetRecord{}, nil
+	}
+	columns.skip(skip)
+	records, err := columns.read(limit)
+	if err != nil {
+		return nil, fmt.Errorf("reading records of %s: %w", filename, err)
+	}
+	return records, nil
+}
+
+// LoadParquet reads input parquet file in batches and creates a graph
+// from the given relationships. Like LoadCsv, whitespace is trimmed
+// from the source and target, and rows where either is empty or null
+// are skipped and counted in the result.
+func LoadParquet(path string, opts LoadOptions) (*Graph, LoadResult, error) {
+	columns, err := openParquetColumns(path)
+	if err != nil {
+		return nil, LoadResult{}, err
+	}
+	defer columns.close()
+	return loadParquetColumns(columns, opts)
+}
+
+// Creates a graph from the rows of the parquet columns like
+// LoadParquet.
+func loadParquetColumns(columns *parquetColumns, opts LoadOptions) (*Graph, LoadResult, error) {
+	result := LoadResult{}
+
+	limit := 1000
+	graph := &Graph{}
+	for result.Rows < columns.rows() {
+		records, err := columns.read(limit)
+		if err != nil {
+			return nil, result, err
+		}
+		if len(records) == 0 {
+			break
+		}
+		for _, record := range records {
+			result.Rows++
+			source, target := strings.TrimSpace(record.Source), strings.TrimSpace(record.Target)
+			if source == "" || target == "" {
+				result.Skipped++
+				opts.logf("skipping row %d with empty source or target", result.Rows)
+			} else if err := opts.insert(graph, source, target, &result); err != nil {
+				return nil, result, err
+			}
+			opts.progress(result.Rows, false)
+		}
+	}
+	opts.progress(result.Rows, true)
+	return graph, result, nil
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// plainRecord is written with plain, non-dictionary encoding and
+// required columns.
+type plainRecord struct {
+	Source string `parquet:"name=source, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN"`
+	Target string `parquet:"name=target, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN"`
+}
+
+// writePlainParquet writes the relations to a parquet file in a
+// temporary directory and returns its path.
+func writePlainParquet(t *testing.T, relations [][2]string) string {
+	filename := filepath.Join(t.TempDir(), "lineage.parquet")
+	fw, err := local.NewLocalFileWriter(filename)
+	if err != nil {
+		t.Fatalf("Unable to create file %s - %v", filename, err)
+	}
+	pw, err := writer.NewParquetWriter(fw, new(plainRecord), 1)
+	if err != nil {
+		t.Fatalf("Unable to create parquet writer - %v", err)
+	}
+	for _, r := range relations {
+		if err := pw.Write(plainRecord{Source: r[0], Target: r[1]}); err != nil {
+			t.Fatalf("Unable to write record - %v", err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		t.Fatalf("Unable to write parquet file - %v", err)
+	}
+	fw.Close()
+	return filename
+}
+
+// TestParquetPlainEncoding reads a parquet file written with plain
+// encoding and checks the constructed graph.
+func TestParquetPlainEncoding(t *testing.T) {
+	filename := writePlainParquet(t, [][2]string{
+		{"stg_orders", "dim_customers"},
+		{"stg_orders", "fct_orders"},
+		{"stg_payments", "fct_orders"},
+		{"stg_payments", "fct_orders"},
+		{" ", "fct_orders"},
+	})
+
+	// assert the fixture is not dictionary encoded
+	fr, err := local.NewLocalFileReader(filename)
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+	pr, err := reader.NewParquetColumnReader(fr, 1)
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+	for _, column := range pr.Footer.RowGroups[0].Columns {
+		for _, encoding := range column.MetaData.Encodings {
+			if encoding == parquet.Encoding_PLAIN_DICTIONARY || encoding == parquet.Encoding_RLE_DICTIONARY {
+				t.Fatalf("Expected plain encoding, Found %v", column.MetaData.Encodings)
+			}
+		}
+	}
+	pr.ReadStop()
+	fr.Close()
+
+	graph, result, err := LoadParquet(filename, LoadOptions{})
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+	if result.Rows != 5 || result.Skipped != 1 {
+		t.Fatalf("Load result mismatch. Expected %v, Found %v", LoadResult{Rows: 5, Skipped: 1}, result)
+	}
+	if len(graph.nodes) != 4 {
+		t.Fatalf(`Node count mismatch. Expected %d, Found %d`, 4, len(graph.nodes))
+	}
+	node := graph.nodes["fct_orders"]
+	sort.Strings(node.upstream)
+	upstream := strings.Join(node.upstream, ",")
+	expectedUpstream := "stg_orders,stg_payments"
+	if upstream != expectedUpstream {
+		t.Fatalf(`Upstream relations mismatch. Expected %v, Found %v`, expectedUpstream, upstream)
+	}
+
+	// ReadParquet pages through the records
+	records, err := ReadParquet(filename, 1, 2)
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+	if len(records) != 2 || records[0].Target != "fct_orders" || records[1].Source != "stg_payments" {
+		t.Fatalf("Records mismatch. Found %v and %v", records[0], records[1])
+	}
+}
@@ -0,0 +1,160 @@
+package graph
+
+import "sort"
+
+// maxPartitionPasses caps the number of refinement passes made by
+// Partition.
+const maxPartitionPasses = 10
+
+// Partition splits the nodes into k groups of roughly equal size while
+// keeping the number of edges between groups low, for processing the
+// graph on several workers. Relations are treated as undirected. Each
+// group is grown greedily from a peripheral node by adding the node
+// with the most edges into the group, and nodes are then moved to the
+// group holding most of their neighbors while that reduces the cut and
+// keeps every group within 10% of the average size. Groups are sorted
+// and ordered by their first path. See CutEdges for the resulting cut.
+// Starting a region scans the unassigned nodes, so graphs with many
+// small components take longer to partition.
+func (g *Graph) Partition(k int) [][]string {
+	paths := g.sortedPaths()
+	n := len(paths)
+	if k > n {
+		k = n
+	}
+	if k <= 1 {
+		if n == 0 {
+			return [][]string{}
+		}
+		return [][]string{paths}
+	}
+
+	part := make(map[string]int, n)
+	sizes := make([]int, k)
+	// edges of a node into the group, or to unassigned nodes if the
+	// group is negative
+	links := func(path string, group int) int {
+		count := 0
+		node := g.nodes[path]
+		for _, neighbors := range [][]string{node.upstream, node.downstream} {
+			for _, nb := range neighbors {
+				if p, ok := part[nb]; ok && p == group || !ok && group < 0 {
+					count++
+				}
+			}
+		}
+		return count
+	}
+
+	for group := 0; group < k-1; group++ {
+		target := n / k
+		if group < n%k {
+			target++
+		}
+		// edges into the group of the unassigned nodes next to it
+		frontier := make(map[string]int)
+		for sizes[group] < target {
+			best, bestLinks := "", 0
+			for path, l := range frontier {
+				if l > bestLinks || l == bestLinks && path < best {
+					best, bestLinks = path, l
+				}
+			}
+			if best == "" {
+				// start a new region from a peripheral node
+				bestLinks = -1
+				for _, path := range paths {
+					if _, ok := part[path]; ok {
+						continue
+					}
+					if l := links(path, -1); bestLinks < 0 || l < bestLinks {
+						best, bestLinks = path, l
+					}
+				}
+			}
+			part[best] = group
+			sizes[group]++
+			delete(frontier, best)
+			node := g.nodes[best]
+			for _, neighbors := range [][]string{node.upstream, node.downstream} {
+				for _, nb := range neighbors {
+					if _, ok := part[nb]; !ok {
+						frontier[nb]++
+					}
+				}
+			}
+		}
+	}
+	for _, path := range paths {
+		if _, ok := part[path]; !ok {
+			part[path] = k - 1
+			sizes[k-1]++
+		}
+	}
+
+	// move nodes to the group holding most of their neighbors, never
+	// emptying a group
+	minSize, maxSize := n*9/(10*k), (n*11+10*k-1)/(10*k)
+	if minSize < 1 {
+		minSize = 1
+	}
+	for pass := 0; pass < maxPartitionPasses; pass++ {
+		moved := false
+		for _, path := range paths {
+			own := part[path]
+			if sizes[own] <= minSize {
+				continue
+			}
+			best, bestLinks := own, links(path, own)
+			for group := 0; group < k; group++ {
+				if group == own || sizes[group] >= maxSize {
+					continue
+				}
+				if l := links(path, group); l > bestLinks {
+					best, bestLinks = group, l
+				}
+			}
+			if best != own {
+				part[path] = best
+				sizes[own]--
+				sizes[best]++
+				moved = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	groups := make([][]string, k)
+	for _, path := range paths {
+		groups[part[path]] = append(groups[part[path]], path)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i]) == 0 || len(groups[j]) == 0 {
+			return len(groups[j]) == 0 && len(groups[i]) > 0
+		}
+		return groups[i][0] < groups[j][0]
+	})
+	return groups
+}
+
+// CutEdges returns the number of edges between nodes in different
+// groups. Nodes that are in no group are ignored.
+func (g *Graph) CutEdges(groups [][]string) int {
+	part := make(map[string]int)
+	for i, group := range groups {
+		for _, path := range group {
+			part[path] = i
+		}
+	}
+	cut := 0
+	for _, edge := range g.edges() {
+		from, okFrom := part[edge.From]
+		to, okTo := part[edge.To]
+		if okFrom && okTo && from != to {
+			cut++
+		}
+	}
+	return cut
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPartition splits two clusters joined by a single bridge edge and
+// asserts that the clusters are separated along the bridge.
+func TestPartition(t *testing.T) {
+	graph := &Graph{}
+	for _, cluster := range []string{"orders", "marketing"} {
+		models := []string{"raw", "stg", "int", "fct", "report"}
+		for i := range models {
+			for j := i + 1; j < len(models); j++ {
+				graph.insert(cluster+"."+models[i], cluster+"."+models[j])
+			}
+		}
+	}
+	graph.insert("orders.fct", "marketing.stg")
+
+	groups := graph.Partition(2)
+	expected := []string{
+		"marketing.fct,marketing.int,marketing.raw,marketing.report,marketing.stg",
+		"orders.fct,orders.int,orders.raw,orders.report,orders.stg",
+	}
+	if len(groups) != 2 {
+		t.Fatalf("Group count mismatch. Expected %d, Found %d", 2, len(groups))
+	}
+	for i := range expected {
+		if result := strings.Join(groups[i], ","); result != expected[i] {
+			t.Fatalf("Group mismatch. Expected %v, Found %v", expected[i], result)
+		}
+	}
+	if cut := graph.CutEdges(groups); cut != 1 {
+		t.Fatalf("Cut mismatch. Expected %d, Found %d", 1, cut)
+	}
+
+	// every node is in exactly one of k groups
+	groups = jaffleShopGraph().Partition(3)
+	count := 0
+	for _, group := range groups {
+		count += len(group)
+	}
+	if len(groups) != 3 || count != 10 {
+		t.Fatalf("Expected 10 nodes in 3 groups, Found %v", groups)
+	}
+
+	// small graphs with k close to the node count get no empty group
+	tests := []struct {
+		edges    []Edge
+		k        int
+		expected string
+	}{
+		{[]Edge{{"a", "b"}}, 2, "a|b"},
+		{[]Edge{{"a", "b"}, {"b", "c"}}, 3, "a|b|c"},
+		{[]Edge{{"a", "b"}, {"c", "d"}}, 4, "a|b|c|d"},
+		{[]Edge{{"a", "b"}, {"c", "d"}}, 3, "a,b|c|d"},
+		{[]Edge{{"a", "b"}}, 5, "a|b"},
+	}
+	for _, test := range tests {
+		small := &Graph{}
+		for _, edge := range test.edges {
+			small.insert(edge.From, edge.To)
+		}
+		found := []string{}
+		for _, group := range small.Partition(test.k) {
+			found = append(found, strings.Join(group, ","))
+		}
+		if result := strings.Join(found, "|"); result != test.expected {
+			t.Fatalf("Group mismatch for %v with k=%d. Expected %v, Found %v", test.edges, test.k, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// duplicateKey normalizes a path for near-duplicate detection. The
+// path is lowercased, surrounding whitespace is trimmed, and a
+// database qualifier is dropped so that db.schema.table and
+// schema.table share a key.
+func duplicateKey(path string) string {
+	key := strings.ToLower(strings.TrimSpace(path))
+	if parts := strings.Split(key, "."); len(parts) > 2 {
+		key = strings.Join(parts[len(parts)-2:], ".")
+	}
+	return key
+}
+
+// FindDuplicateCandidates returns groups of paths that differ only by
+// case, surrounding whitespace or a database qualifier and likely
+// refer to the same entity. Each group is sorted and the groups are
+// sorted by their first path.
+func (g *Graph) FindDuplicateCandidates() [][]string {
+	groups := make(map[string][]string)
+	for _, path := range g.sortedPaths() {
+		key := duplicateKey(path)
+		groups[key] = append(groups[key], path)
+	}
+
+	result := [][]string{}
+	for _, group := range groups {
+		if len(group) > 1 {
+			result = append(result, group)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i][0] < result[j][0]
+	})
+	return result
+}
+
+// OrphanSources returns the sorted paths of nodes with neither
+// upstream nor downstream relations. Such a source was loaded but
+// feeds nothing, which usually signals a broken lineage export.
+func (g *Graph) OrphanSources() []string {
+	orphans := []string{}
+	for path, node := range g.nodes {
+		if len(node.upstream) == 0 && len(node.downstream) == 0 {
+			orphans = append(orphans, path)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// NamingRule is a layering convention checked by ValidateNaming. The
+// patterns are globs where "*" matches any run of characters and "?"
+// matches a single character.
+type NamingRule struct {
+	// Nodes selects the nodes the rule applies to.
+	Nodes string
+	// Upstream is the pattern every immediate upstream of the
+	// selected nodes must match.
+	Upstream string
+}
+
+// Violation reports an upstream relation of a node that breaks a
+// naming rule.
+type Violation struct {
+	Rule     NamingRule
+	Path     string
+	Upstream string
+}
+
+// Returns a regular expression matching the whole of a path against
+// the glob pattern.
+func globRegexp(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return regexp.MustCompile("^" + quoted + "$")
+}
+
+// ValidateNaming checks every node against the rules and returns a
+// violation for each immediate upstream of a node selected by a rule
+// that does not match the rule's upstream pattern. Violations are
+// sorted by path, then by upstream, then in rule order.
+func (g *Graph) ValidateNaming(rules []NamingRule) []Violation {
+	violations := []Violation{}
+	for _, rule := range rules {
+		nodes, upstream := globRegexp(rule.Nodes), globRegexp(rule.Upstream)
+		for _, path := range g.sortedPaths() {
+			if !nodes.MatchString(path) {
+				continue
+			}
+			for _, up := range g.nodes[path].upstream {
+				if !upstream.MatchString(up) {
+					violations = append(violations, Violation{Rule: rule, Path: path, Upstream: up})
+				}
+			}
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Upstream < b.Upstream
+	})
+	return violations
+}
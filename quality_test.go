@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFindDuplicateCandidates asserts that paths differing by case,
+// whitespace or database qualifier are grouped together.
+func TestFindDuplicateCandidates(t *testing.T) {
+	graph := &Graph{}
+	graph.insert("schema.tbl", "report")
+	graph.insert("SCHEMA.TBL", "dashboard")
+	graph.insert("warehouse.schema.tbl ", "dashboard")
+	graph.insert("schema.other", "report")
+
+	groups := graph.FindDuplicateCandidates()
+	if len(groups) != 1 {
+		t.Fatalf("Group count mismatch. Expected %d, Found %d", 1, len(groups))
+	}
+	found := strings.Join(groups[0], ",")
+	expected := "SCHEMA.TBL,schema.tbl,warehouse.schema.tbl "
+	if found != expected {
+		t.Fatalf("Group mismatch. Expected %v, Found %v", expected, found)
+	}
+
+	// the jaffle_shop graph has no duplicates
+	if groups := jaffleShopGraph().FindDuplicateCandidates(); len(groups) != 0 {
+		t.Fatalf("Group count mismatch. Expected %d, Found %v", 0, groups)
+	}
+}
+
+// TestOrphanSources asserts that a disconnected source is reported
+// while sources that feed the lineage are not.
+func TestOrphanSources(t *testing.T) {
+	graph := jaffleShopGraph()
+	if orphans := graph.OrphanSources(); len(orphans) != 0 {
+		t.Fatalf("Orphans mismatch. Expected %v, Found %v", []string{}, orphans)
+	}
+
+	graph.getOrCreate("hubspot.contacts")
+	orphans := graph.OrphanSources()
+	if strings.Join(orphans, ",") != "hubspot.contacts" {
+		t.Fatalf("Orphans mismatch. Expected %v, Found %v", []string{"hubspot.contacts"}, orphans)
+	}
+}
+
+// TestValidateNaming checks that dim_* nodes may only derive from
+// stg_* nodes and reports a dim_ node fed by a raw source.
+func TestValidateNaming(t *testing.T) {
+	graph := jaffleShopGraph()
+	rules := []NamingRule{{Nodes: "dim_*", Upstream: "stg_*"}}
+	if violations := graph.ValidateNaming(rules); len(violations) != 0 {
+		t.Fatalf("Violations mismatch. Expected %v, Found %v", []Violation{}, violations)
+	}
+
+	graph.insert("jaffle_shop.customers", "dim_customers")
+	violations := graph.ValidateNaming(rules)
+	expected := Violation{Rule: rules[0], Path: "dim_customers", Upstream: "jaffle_shop.customers"}
+	if len(violations) != 1 || violations[0] != expected {
+		t.Fatalf("Violations mismatch. Expected %v, Found %v", []Violation{expected}, violations)
+	}
+
+	// pattern characters other than "*" and "?" are literal, so
+	// stg_customers does not match stg.customers
+	rules = []NamingRule{{Nodes: "dim_customers", Upstream: "stg.customers"}}
+	if violations := graph.ValidateNaming(rules); len(violations) != 3 {
+		t.Fatalf("Violation count mismatch. Expected %d, Found %d", 3, len(violations))
+	}
+}
@@ -0,0 +1,345 @@
+package graph
+
+import "sort"
+
+// UpstreamBySource groups the upstream of the given path by the root
+// sources it traces back to. Each root (an ancestor with no upstream
+// of its own) is mapped to itself and the ancestors of the path that
+// lie on a lineage from that root. Ancestors fed by several roots
+// appear in each of their groups.
+func (g *Graph) UpstreamBySource(path string) (map[string][]string, error) {
+	ancestors, err := g.upstream([]string{path})
+	if err != nil {
+		return nil, err
+	}
+	isAncestor := make(map[string]bool, len(ancestors))
+	for _, a := range ancestors {
+		isAncestor[a] = true
+	}
+
+	result := make(map[string][]string)
+	for _, a := range ancestors {
+		if len(g.nodes[a].upstream) != 0 {
+			continue
+		}
+		downstream, err := g.downstream([]string{a})
+		if err != nil {
+			return nil, err
+		}
+		// keep only the descendants of the root that feed the path
+		group := []string{a}
+		for _, d := range downstream {
+			if isAncestor[d] {
+				group = append(group, d)
+			}
+		}
+		sort.Strings(group)
+		result[a] = group
+	}
+	return result, nil
+}
+
+// maxPathCount caps the number of paths counted by PathCount so that
+// densely connected graphs do not blow up the count.
+const maxPathCount = 1000000
+
+// PathCount returns the number of distinct downstream paths between
+// two nodes, saturating at maxPathCount. A node has exactly one path
+// to itself. The graph is assumed to be acyclic; paths that would
+// revisit a node are not counted.
+func (g *Graph) PathCount(from, to string) (int, error) {
+	if _, ok := g.nodes[from]; !ok {
+		return 0, &MissingNodeError{path: from}
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return 0, &MissingNodeError{path: to}
+	}
+
+	return g.pathsTo(to)(from), nil
+}
+
+// Returns a function counting the distinct downstream paths from a
+// node to the given path like PathCount. The counts are memoized
+// across calls.
+func (g *Graph) pathsTo(to string) func(from string) int {
+	counts := make(map[string]int)
+	var count func(path string) int
+	count = func(path string) int {
+		if path == to {
+			return 1
+		}
+		if c, ok := counts[path]; ok {
+			return c
+		}
+		// mark the node before descending so that a cycle
+		// contributes no paths instead of recursing forever
+		counts[path] = 0
+		total := 0
+		for _, ds := range g.nodes[path].downstream {
+			total += count(ds)
+			if total >= maxPathCount {
+				total = maxPathCount
+				break
+			}
+		}
+		counts[path] = total
+		return total
+	}
+	return count
+}
+
+// UpstreamContribution maps every root ancestor of the target to the
+// number of distinct paths from it to the target, counted like
+// PathCount. Sources reaching the target through more paths
+// contribute more to it.
+func (g *Graph) UpstreamContribution(target string) (map[string]int, error) {
+	ancestors, err := g.upstream([]string{target})
+	if err != nil {
+		return nil, err
+	}
+	count := g.pathsTo(target)
+	contribution := make(map[string]int)
+	for _, a := range ancestors {
+		if len(g.nodes[a].upstream) == 0 {
+			contribution[a] = count(a)
+		}
+	}
+	return contribution, nil
+}
+
+// upstreamOf selects the upstream relations of a node to follow when
+// walking the graph.
+func upstreamOf(n *Node) []string {
+	return n.upstream
+}
+
+// downstreamOf selects the downstream relations of a node to follow
+// when walking the graph.
+func downstreamOf(n *Node) []string {
+	return n.downstream
+}
+
+// distances walks the graph breadth first from the given paths along
+// the relations selected by next and returns the hop distance of every
+// node reached from the nearest path. Nodes further than maxDepth hops
+// are not reached; a negative maxDepth does not limit the walk. The
+// given paths are at distance zero from themselves and are left out,
+// even when they are reached from one another.
+func (g *Graph) distances(paths []string, next func(*Node) []string, maxDepth int) (map[string]int, error) {
+	found := make(map[string]int)
+	processed := make(map[string]bool)
+	seeds := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		seeds[path] = true
+	}
+	for depth := 0; len(paths) > 0 && (maxDepth < 0 || depth < maxDepth); depth++ {
+		frontier := []string{}
+		for _, path := range paths {
+			if processed[path] {
+				// skip path if it is already processed
+				continue
+			}
+			processed[path] = true
+			node, ok := g.nodes[path]
+			if !ok {
+				return nil, &MissingNodeError{path: path}
+			}
+			for _, n := range next(node) {
+				if _, ok := found[n]; !ok && !seeds[n] {
+					found[n] = depth + 1
+				}
+				if !processed[n] {
+					frontier = append(frontier, n)
+				}
+			}
+		}
+		paths = frontier
+	}
+	return found, nil
+}
+
+// DownstreamDistances returns every downstream node of the given
+// paths mapped to its hop distance from the nearest path. The given
+// paths themselves are left out.
+func (g *Graph) DownstreamDistances(paths []string) (map[string]int, error) {
+	return g.distances(paths, downstreamOf, -1)
+}
+
+// LocalRoots returns the given paths that have no upstream among the
+// given paths, i.e. the roots of the subgraph induced by the paths.
+func (g *Graph) LocalRoots(paths []string) ([]string, error) {
+	return g.localEnds(paths, upstreamOf)
+}
+
+// LocalLeaves returns the given paths that have no downstream among
+// the given paths, i.e. the leaves of the subgraph induced by the
+// paths.
+func (g *Graph) LocalLeaves(paths []string) ([]string, error) {
+	return g.localEnds(paths, downstreamOf)
+}
+
+// Returns the sorted paths that have no relation selected by next to
+// another of the given paths.
+func (g *Graph) localEnds(paths []string, next func(*Node) []string) ([]string, error) {
+	selected := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if _, ok := g.nodes[path]; !ok {
+			return nil, &MissingNodeError{path: path}
+		}
+		selected[path] = true
+	}
+
+	result := []string{}
+	for path := range selected {
+		end := true
+		for _, n := range next(g.nodes[path]) {
+			if selected[n] {
+				end = false
+				break
+			}
+		}
+		if end {
+			result = append(result, path)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// CheckReachable answers for every (from, to) pair whether to is
+// downstream of from. The downstream of each distinct from path is
+// computed once and shared by all of its pairs.
+func (g *Graph) CheckReachable(pairs [][2]string) (map[[2]string]bool, error) {
+	closures := make(map[string]map[string]bool)
+	result := make(map[[2]string]bool, len(pairs))
+	for _, pair := range pairs {
+		from, to := pair[0], pair[1]
+		if _, ok := g.nodes[to]; !ok {
+			return nil, &MissingNodeError{path: to}
+		}
+		closure, ok := closures[from]
+		if !ok {
+			downstream, err := g.downstream([]string{from})
+			if err != nil {
+				return nil, err
+			}
+			closure = make(map[string]bool, len(downstream))
+			for _, ds := range downstream {
+				closure[ds] = true
+			}
+			closures[from] = closure
+		}
+		result[pair] = closure[to]
+	}
+	return result, nil
+}
+
+// PathLengthsAmong returns the shortest downstream hop count for every
+// ordered pair of the given nodes where the second is downstream of
+// the first. Pairs that are not connected, and a node paired with
+// itself, are left out.
+func (g *Graph) PathLengthsAmong(nodes []string) (map[[2]string]int, error) {
+	for _, path := range nodes {
+		if _, ok := g.nodes[path]; !ok {
+			return nil, &MissingNodeError{path: path}
+		}
+	}
+	lengths := make(map[[2]string]int)
+	for _, from := range nodes {
+		distances, err := g.distances([]string{from}, downstreamOf, -1)
+		if err != nil {
+			return nil, err
+		}
+		for _, to := range nodes {
+			if d, ok := distances[to]; ok && to != from {
+				lengths[[2]string{from, to}] = d
+			}
+		}
+	}
+	return lengths, nil
+}
+
+// NeighborsAtDistance returns the nodes exactly k hops upstream and
+// exactly k hops downstream of the given path, where a node's distance
+// is its shortest hop count from the path. Both are sorted and empty
+// for k below 1.
+func (g *Graph) NeighborsAtDistance(path string, k int) (upstream []string, downstream []string, err error) {
+	if _, ok := g.nodes[path]; !ok {
+		return nil, nil, &MissingNodeError{path: path}
+	}
+	upstream, downstream = []string{}, []string{}
+	if k < 1 {
+		return upstream, downstream, nil
+	}
+	ring := func(next func(*Node) []string) ([]string, error) {
+		distances, err := g.distances([]string{path}, next, k)
+		if err != nil {
+			return nil, err
+		}
+		result := []string{}
+		for p, d := range distances {
+			if d == k {
+				result = append(result, p)
+			}
+		}
+		sort.Strings(result)
+		return result, nil
+	}
+	if upstream, err = ring(upstreamOf); err != nil {
+		return nil, nil, err
+	}
+	if downstream, err = ring(downstreamOf); err != nil {
+		return nil, nil, err
+	}
+	return upstream, downstream, nil
+}
+
+// FanOutGuard limits how a traversal expands nodes with a pathological
+// number of downstream relations.
+type FanOutGuard struct {
+	// MaxFanOut is the out-degree above which a node is capped. A
+	// MaxFanOut of zero or less caps no node.
+	MaxFanOut int
+	// Stop, if true, does not expand capped nodes. Otherwise capped
+	// nodes are only reported.
+	Stop bool
+}
+
+// DownstreamGuarded gets the downstream nodes for the given paths like
+// downstream, and also returns the sorted paths of the expanded nodes
+// whose out-degree exceeds the guard's MaxFanOut. When the guard stops
+// at capped nodes, their downstream relations are not followed, so
+// their children are only included if they are reached another way.
+func (g *Graph) DownstreamGuarded(paths []string, guard FanOutGuard) (downstream []string, capped []string, err error) {
+	capped = []string{}
+	// record the capped nodes as they are expanded, and do not follow
+	// their relations when the guard stops at them
+	guarded := func(n *Node) []string {
+		if guard.MaxFanOut > 0 && len(n.downstream) > guard.MaxFanOut {
+			capped = append(capped, n.path)
+			if guard.Stop {
+				return nil
+			}
+		}
+		return n.downstream
+	}
+	if downstream, _, err = g.traverse(paths, guarded, -1); err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(capped)
+	return downstream, capped, nil
+}
+
+// ValidateSeeds returns the given paths that are not in the graph,
+// sorted and without duplicates, so that every bad seed can be
+// reported at once before a traversal fails on the first one.
+func (g *Graph) ValidateSeeds(paths []string) []string {
+	missing := []string{}
+	for _, path := range paths {
+		if _, ok := g.nodes[path]; !ok && !contains(missing, path) {
+			missing = append(missing, path)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,367 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestUpstreamBySource asserts that the upstream of a node fed by two
+// sources is split into the two lineage branches.
+func TestUpstreamBySource(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	// Query: graph.UpstreamBySource(fct_orders)
+	// Result: jaffle_shop.orders -> [jaffle_shop.orders, stg_orders]
+	//         stripe.payment -> [stg_payments, stripe.payment]
+	groups, err := graph.UpstreamBySource("fct_orders")
+	if err != nil {
+		t.Fatalf("Error getting upstream by source - %v", err)
+	}
+	expected := map[string]string{
+		"jaffle_shop.orders": "jaffle_shop.orders,stg_orders",
+		"stripe.payment":     "stg_payments,stripe.payment",
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("Source count mismatch. Expected %d, Found %d", len(expected), len(groups))
+	}
+	for source, want := range expected {
+		if got := strings.Join(groups[source], ","); got != want {
+			t.Fatalf("Group mismatch for %s. Expected %v, Found %v", source, want, got)
+		}
+	}
+
+	// Query: graph.UpstreamBySource(missing)
+	// Result: MissingNodeError
+	if _, err := graph.UpstreamBySource("missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestPathCount asserts the number of paths on a diamond and on a
+// linear chain.
+func TestPathCount(t *testing.T) {
+	diamond := &Graph{}
+	diamond.insert("a", "b")
+	diamond.insert("a", "c")
+	diamond.insert("b", "d")
+	diamond.insert("c", "d")
+
+	count, err := diamond.PathCount("a", "d")
+	if err != nil {
+		t.Fatalf("Error counting paths - %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("Path count mismatch. Expected %d, Found %d", 2, count)
+	}
+
+	chain := &Graph{}
+	chain.insert("a", "b")
+	chain.insert("b", "c")
+	chain.insert("c", "d")
+
+	count, err = chain.PathCount("a", "d")
+	if err != nil {
+		t.Fatalf("Error counting paths - %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("Path count mismatch. Expected %d, Found %d", 1, count)
+	}
+
+	// no path against the direction of the edges
+	count, err = chain.PathCount("d", "a")
+	if err != nil {
+		t.Fatalf("Error counting paths - %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("Path count mismatch. Expected %d, Found %d", 0, count)
+	}
+}
+
+// TestDownstreamDistances asserts the hop distances of the downstream
+// of stg_orders.
+func TestDownstreamDistances(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	distances, err := graph.DownstreamDistances([]string{"stg_orders"})
+	if err != nil {
+		t.Fatalf("Error getting downstream distances - %v", err)
+	}
+	expected := map[string]int{
+		"dim_customers":         1,
+		"fct_orders":            1,
+		"weekly_jaffle_metrics": 2,
+	}
+	if len(distances) != len(expected) {
+		t.Fatalf("Downstream count mismatch. Expected %v, Found %v", expected, distances)
+	}
+	for path, want := range expected {
+		if distances[path] != want {
+			t.Fatalf("Distance mismatch for %s. Expected %d, Found %d", path, want, distances[path])
+		}
+	}
+
+	// distances are measured from the nearest seed
+	distances, err = graph.DownstreamDistances([]string{"stg_orders", "fct_orders"})
+	if err != nil {
+		t.Fatalf("Error getting downstream distances - %v", err)
+	}
+	if distances["weekly_jaffle_metrics"] != 1 {
+		t.Fatalf("Distance mismatch for %s. Expected %d, Found %d", "weekly_jaffle_metrics", 1, distances["weekly_jaffle_metrics"])
+	}
+	// a seed reached from another seed is left out
+	if d, ok := distances["fct_orders"]; ok {
+		t.Fatalf("Expected seed %s to be left out, Found distance %d", "fct_orders", d)
+	}
+	if len(distances) != 2 {
+		t.Fatalf("Downstream count mismatch. Expected %d, Found %v", 2, distances)
+	}
+}
+
+// TestLocalRootsAndLeaves asserts the entry and exit points among a
+// selection of nodes.
+func TestLocalRootsAndLeaves(t *testing.T) {
+	graph := jaffleShopGraph()
+	selection := []string{"stg_orders", "dim_customers", "fct_orders", "weekly_jaffle_metrics"}
+
+	roots, err := graph.LocalRoots(selection)
+	if err != nil {
+		t.Fatalf("Error getting local roots - %v", err)
+	}
+	if strings.Join(roots, ",") != "stg_orders" {
+		t.Fatalf("Local roots mismatch. Expected %v, Found %v", []string{"stg_orders"}, roots)
+	}
+
+	leaves, err := graph.LocalLeaves(selection)
+	if err != nil {
+		t.Fatalf("Error getting local leaves - %v", err)
+	}
+	if strings.Join(leaves, ",") != "weekly_jaffle_metrics" {
+		t.Fatalf("Local leaves mismatch. Expected %v, Found %v", []string{"weekly_jaffle_metrics"}, leaves)
+	}
+
+	if _, err := graph.LocalRoots([]string{"stg_orders", "missing"}); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestCheckReachable asserts reachability for several pairs on the
+// jaffle_shop graph.
+func TestCheckReachable(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	expected := map[[2]string]bool{
+		{"jaffle_shop.orders", "weekly_jaffle_metrics"}: true,
+		{"jaffle_shop.orders", "fct_orders"}:            true,
+		{"jaffle_shop.orders", "stg_payments"}:          false,
+		{"stg_payments", "dim_customers"}:               false,
+		{"weekly_jaffle_metrics", "stg_orders"}:         false,
+		{"gsheets.goals", "weekly_jaffle_metrics"}:      true,
+	}
+	pairs := [][2]string{}
+	for pair := range expected {
+		pairs = append(pairs, pair)
+	}
+	reachable, err := graph.CheckReachable(pairs)
+	if err != nil {
+		t.Fatalf("Error checking reachability - %v", err)
+	}
+	for pair, want := range expected {
+		if reachable[pair] != want {
+			t.Fatalf("Reachability mismatch for %v. Expected %v, Found %v", pair, want, reachable[pair])
+		}
+	}
+
+	if _, err := graph.CheckReachable([][2]string{{"stg_orders", "missing"}}); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestPathLengthsAmong checks the shortest hop counts among a few
+// jaffle_shop nodes.
+func TestPathLengthsAmong(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	lengths, err := graph.PathLengthsAmong([]string{"stg_orders", "fct_orders", "weekly_jaffle_metrics", "stg_payments"})
+	if err != nil {
+		t.Fatalf("Error getting path lengths - %v", err)
+	}
+	expected := map[[2]string]int{
+		{"stg_orders", "fct_orders"}:              1,
+		{"stg_orders", "weekly_jaffle_metrics"}:   2,
+		{"fct_orders", "weekly_jaffle_metrics"}:   1,
+		{"stg_payments", "fct_orders"}:            1,
+		{"stg_payments", "weekly_jaffle_metrics"}: 2,
+	}
+	if len(lengths) != len(expected) {
+		t.Fatalf("Pair count mismatch. Expected %d, Found %d", len(expected), len(lengths))
+	}
+	for pair, want := range expected {
+		if lengths[pair] != want {
+			t.Fatalf("Length mismatch for %v. Expected %d, Found %d", pair, want, lengths[pair])
+		}
+	}
+
+	if _, err := graph.PathLengthsAmong([]string{"stg_orders", "missing"}); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// reachablePairs builds a chain graph and many pairs sharing a few
+// sources for the reachability benchmarks.
+func reachablePairs() (*Graph, [][2]string) {
+	graph := &Graph{}
+	for i := 0; i < 1000; i++ {
+		graph.insert(strconv.Itoa(i), strconv.Itoa(i+1))
+	}
+	pairs := [][2]string{}
+	for from := 0; from < 10; from++ {
+		for to := 0; to < 1000; to += 10 {
+			pairs = append(pairs, [2]string{strconv.Itoa(from), strconv.Itoa(to)})
+		}
+	}
+	return graph, pairs
+}
+
+// BenchmarkCheckReachable checks all pairs in a single call.
+func BenchmarkCheckReachable(b *testing.B) {
+	graph, pairs := reachablePairs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := graph.CheckReachable(pairs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCheckReachableNaive computes the downstream of every pair.
+func BenchmarkCheckReachableNaive(b *testing.B) {
+	graph, pairs := reachablePairs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, pair := range pairs {
+			downstream, err := graph.downstream([]string{pair[0]})
+			if err != nil {
+				b.Fatal(err)
+			}
+			contains(downstream, pair[1])
+		}
+	}
+}
+
+// TestNeighborsAtDistance asserts the rings of nodes around
+// fct_orders.
+func TestNeighborsAtDistance(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	upstream, downstream, err := graph.NeighborsAtDistance("fct_orders", 2)
+	if err != nil {
+		t.Fatalf("Error getting neighbors - %v", err)
+	}
+	expected := "jaffle_shop.orders,stripe.payment"
+	if strings.Join(upstream, ",") != expected {
+		t.Fatalf("Upstream ring mismatch. Expected %v, Found %v", expected, upstream)
+	}
+	if len(downstream) != 0 {
+		t.Fatalf("Downstream ring mismatch. Expected %v, Found %v", []string{}, downstream)
+	}
+
+	upstream, downstream, err = graph.NeighborsAtDistance("stg_orders", 2)
+	if err != nil {
+		t.Fatalf("Error getting neighbors - %v", err)
+	}
+	if len(upstream) != 0 || strings.Join(downstream, ",") != "weekly_jaffle_metrics" {
+		t.Fatalf("Ring mismatch. Expected [] and [weekly_jaffle_metrics], Found %v and %v", upstream, downstream)
+	}
+
+	if _, _, err := graph.NeighborsAtDistance("missing", 1); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestDownstreamGuarded asserts that a high fan-out node is reported
+// and that its children are not expanded when the guard stops.
+func TestDownstreamGuarded(t *testing.T) {
+	graph := &Graph{}
+	graph.insert("raw.customers", "dim_customers")
+	for i := 0; i < 100; i++ {
+		graph.insert("dim_customers", "model_"+strconv.Itoa(i))
+	}
+	graph.insert("raw.customers", "model_0")
+
+	downstream, capped, err := graph.DownstreamGuarded([]string{"raw.customers"}, FanOutGuard{MaxFanOut: 10, Stop: true})
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	if strings.Join(capped, ",") != "dim_customers" {
+		t.Fatalf("Capped mismatch. Expected %v, Found %v", []string{"dim_customers"}, capped)
+	}
+	// model_0 is reached directly, the other children are not expanded
+	if len(downstream) != 2 || !contains(downstream, "dim_customers") || !contains(downstream, "model_0") {
+		t.Fatalf("Downstream mismatch. Found %v", downstream)
+	}
+
+	downstream, capped, err = graph.DownstreamGuarded([]string{"raw.customers"}, FanOutGuard{MaxFanOut: 10})
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	if len(capped) != 1 || len(downstream) != 101 {
+		t.Fatalf("Expected all 101 downstream nodes and 1 capped node, Found %d and %v", len(downstream), capped)
+	}
+
+	// the zero value guard caps nothing, even when stopping
+	downstream, capped, err = graph.DownstreamGuarded([]string{"raw.customers"}, FanOutGuard{Stop: true})
+	if err != nil {
+		t.Fatalf("Error getting downstream - %v", err)
+	}
+	if len(capped) != 0 || len(downstream) != 101 {
+		t.Fatalf("Expected all 101 downstream nodes and no capped node, Found %d and %v", len(downstream), capped)
+	}
+}
+
+// TestValidateSeeds passes several missing seeds and asserts that all
+// of them are returned sorted.
+func TestValidateSeeds(t *testing.T) {
+	graph := jaffleShopGraph()
+	missing := graph.ValidateSeeds([]string{"stg_payments", "stg_refunds", "fct_orders", "raw.events", "stg_refunds"})
+	expected := "raw.events,stg_refunds"
+	if result := strings.Join(missing, ","); result != expected {
+		t.Fatalf("Missing seeds mismatch. Expected %v, Found %v", expected, result)
+	}
+
+	if missing := graph.ValidateSeeds([]string{"stg_orders"}); len(missing) != 0 {
+		t.Fatalf("Missing seeds mismatch. Expected %v, Found %v", []string{}, missing)
+	}
+}
+
+// TestUpstreamContribution asserts that a source feeding the target
+// through two paths outranks a source with a single path.
+func TestUpstreamContribution(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	// Query: graph.UpstreamContribution(weekly_jaffle_metrics)
+	// Result: jaffle_shop.orders reaches it through dim_customers and
+	// fct_orders, the other sources through a single path
+	contribution, err := graph.UpstreamContribution("weekly_jaffle_metrics")
+	if err != nil {
+		t.Fatalf("Error getting upstream contribution - %v", err)
+	}
+	expected := map[string]int{
+		"jaffle_shop.customers": 1,
+		"jaffle_shop.orders":    2,
+		"stripe.payment":        1,
+		"gsheets.goals":         1,
+	}
+	if len(contribution) != len(expected) {
+		t.Fatalf("Contribution mismatch. Expected %v, Found %v", expected, contribution)
+	}
+	for source, count := range expected {
+		if contribution[source] != count {
+			t.Fatalf("Contribution mismatch for %s. Expected %d, Found %d", source, count, contribution[source])
+		}
+	}
+
+	if _, err := graph.UpstreamContribution("missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
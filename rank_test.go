@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestPersonalizedPageRank asserts that ancestors of the seed score
+// higher than unrelated nodes.
+func TestPersonalizedPageRank(t *testing.T) {
+	graph := jaffleShopGraph()
+	graph.insert("hubspot.contacts", "stg_contacts")
+	graph.insert("stg_contacts", "marketing_report")
+
+	rank := graph.PersonalizedPageRank([]string{"fct_orders"}, 0.85, 50)
+
+	total := 0.0
+	for _, score := range rank {
+		total += score
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Fatalf("Rank total mismatch. Expected %v, Found %v", 1, total)
+	}
+
+	ancestors := []string{"stg_orders", "stg_payments", "jaffle_shop.orders", "stripe.payment"}
+	unrelated := []string{"hubspot.contacts", "stg_contacts", "marketing_report", "weekly_jaffle_metrics", "dim_customers"}
+	for _, a := range ancestors {
+		for _, u := range unrelated {
+			if rank[a] <= rank[u] {
+				t.Fatalf("Expected %s (%v) to outrank %s (%v)", a, rank[a], u, rank[u])
+			}
+		}
+	}
+}
+
+// TestRandomWalk checks that a walk from a root is reproducible for a
+// fixed seed and only visits downstream nodes of the root.
+func TestRandomWalk(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	walk, err := graph.RandomWalk("jaffle_shop.orders", 10, 7)
+	if err != nil {
+		t.Fatalf("Error walking graph - %v", err)
+	}
+	// the longest path from jaffle_shop.orders has three edges
+	if len(walk) != 4 || walk[0] != "jaffle_shop.orders" || walk[3] != "weekly_jaffle_metrics" {
+		t.Fatalf("Walk mismatch. Expected %v, Found %v", "4 nodes to weekly_jaffle_metrics", walk)
+	}
+	downstream, _ := graph.downstream([]string{"jaffle_shop.orders"})
+	for _, path := range walk[1:] {
+		if !contains(downstream, path) {
+			t.Fatalf("Expected %s to be downstream of jaffle_shop.orders", path)
+		}
+	}
+	again, _ := jaffleShopGraph().RandomWalk("jaffle_shop.orders", 10, 7)
+	if strings.Join(again, ",") != strings.Join(walk, ",") {
+		t.Fatalf("Walk mismatch. Expected %v, Found %v", walk, again)
+	}
+
+	if walk, _ := graph.RandomWalk("stg_orders", 1, 7); len(walk) != 2 {
+		t.Fatalf("Walk length mismatch. Expected %d, Found %d", 2, len(walk))
+	}
+	if _, err := graph.RandomWalk("missing", 1, 7); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// ErrDotNotFound is returned by RenderSVG when the Graphviz dot binary
+// is not installed or not in the PATH.
+var ErrDotNotFound = errors.New("graphviz dot binary not found in PATH")
+
+// RenderSVG lays out the graph with the Graphviz dot binary and writes
+// the resulting SVG. Returns ErrDotNotFound if dot is not installed.
+func (g *Graph) RenderSVG(w io.Writer) error {
+	dot, err := exec.LookPath("dot")
+	if err != nil {
+		return ErrDotNotFound
+	}
+	var input bytes.Buffer
+	if err := g.ToDOT(&input); err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(dot, "-Tsvg")
+	cmd.Stdin = &input
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running dot: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
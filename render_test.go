@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestRenderSVG renders the jaffle_shop graph and asserts that the
+// output is an SVG document. Skipped if dot is not installed.
+func TestRenderSVG(t *testing.T) {
+	if _, err := exec.LookPath("dot"); err != nil {
+		t.Skip("dot is not installed")
+	}
+	var buf bytes.Buffer
+	if err := jaffleShopGraph().RenderSVG(&buf); err != nil {
+		t.Fatalf("Unable to render SVG - %v", err)
+	}
+	// dot writes an XML declaration, doctype and comments before the
+	// svg tag, so only those may precede it
+	output := buf.String()
+	start := strings.Index(output, "<svg")
+	if start < 0 || strings.Contains(output[:start], "<g") {
+		t.Fatalf("Expected SVG output, Found %q", output)
+	}
+}
+
+// TestRenderSVGWithoutDot asserts the error returned when dot cannot
+// be found.
+func TestRenderSVGWithoutDot(t *testing.T) {
+	t.Setenv("PATH", "")
+	var buf bytes.Buffer
+	if err := jaffleShopGraph().RenderSVG(&buf); !errors.Is(err, ErrDotNotFound) {
+		t.Fatalf("Error mismatch. Expected %v, Found %v", ErrDotNotFound, err)
+	}
+}
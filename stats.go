@@ -0,0 +1,88 @@
+package graph
+
+import "fmt"
+
+// Stats holds summary counts for a graph.
+type Stats struct {
+	Nodes  int
+	Edges  int
+	Roots  int
+	Leaves int
+	DAG    bool
+}
+
+// Stats computes the summary counts for the graph. Roots are nodes with
+// no upstream and leaves are nodes with no downstream; an isolated node
+// is both.
+func (g *Graph) Stats() Stats {
+	stats := Stats{Nodes: len(g.nodes)}
+	for _, node := range g.nodes {
+		stats.Edges += len(node.downstream)
+		if len(node.upstream) == 0 {
+			stats.Roots++
+		}
+		if len(node.downstream) == 0 {
+			stats.Leaves++
+		}
+	}
+	_, err := g.topologicalOrder()
+	stats.DAG = err == nil
+	return stats
+}
+
+// Summary returns a one line description of the graph for logging,
+// like "nodes=10 edges=10 roots=4 leaves=1 dag=true".
+func (g *Graph) Summary() string {
+	s := g.Stats()
+	return fmt.Sprintf("nodes=%d edges=%d roots=%d leaves=%d dag=%t", s.Nodes, s.Edges, s.Roots, s.Leaves, s.DAG)
+}
+
+// LeafSourceCounts maps every leaf to the number of distinct roots that
+// feed it. Isolated nodes, which are their own root, are not included.
+func (g *Graph) LeafSourceCounts() map[string]int {
+	counts := make(map[string]int)
+	for path, node := range g.nodes {
+		if len(node.downstream) != 0 || len(node.upstream) == 0 {
+			continue
+		}
+		// leaves always have an upstream, so the traversal cannot fail
+		upstream, _ := g.upstream([]string{path})
+		for _, up := range upstream {
+			if len(g.nodes[up].upstream) == 0 {
+				counts[path]++
+			}
+		}
+	}
+	return counts
+}
+
+// SourceLeafMatrix returns the sorted roots as sources, the sorted
+// leaves, and a matrix where reaches[i][j] reports whether source i
+// feeds leaf j. Isolated nodes are neither sources nor leaves.
+func (g *Graph) SourceLeafMatrix() (sources, leaves []string, reaches [][]bool) {
+	sources, leaves = []string{}, []string{}
+	column := make(map[string]int)
+	for _, path := range g.sortedPaths() {
+		node := g.nodes[path]
+		switch {
+		case len(node.upstream) == 0 && len(node.downstream) != 0:
+			sources = append(sources, path)
+		case len(node.downstream) == 0 && len(node.upstream) != 0:
+			column[path] = len(leaves)
+			leaves = append(leaves, path)
+		}
+	}
+
+	reaches = make([][]bool, len(sources))
+	for i, source := range sources {
+		reaches[i] = make([]bool, len(leaves))
+		// sources are in the graph, so the traversal cannot fail
+		downstream, _ := g.downstream([]string{source})
+		for _, ds := range downstream {
+			if j, ok := column[ds]; ok {
+				reaches[i][j] = true
+			}
+		}
+	}
+	return sources, leaves, reaches
+}
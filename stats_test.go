@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSummary asserts the summary of the jaffle_shop graph.
+func TestSummary(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	summary := graph.Summary()
+	expected := "nodes=10 edges=10 roots=4 leaves=1 dag=true"
+	if summary != expected {
+		t.Fatalf("Summary mismatch. Expected %q, Found %q", expected, summary)
+	}
+
+	graph.insert("weekly_jaffle_metrics", "stg_orders")
+	if summary := graph.Summary(); !strings.HasSuffix(summary, "leaves=0 dag=false") {
+		t.Fatalf("Summary mismatch. Expected a cyclic graph, Found %q", summary)
+	}
+}
+
+// TestLeafSourceCounts asserts the number of sources feeding the
+// leaves of the jaffle_shop graph.
+func TestLeafSourceCounts(t *testing.T) {
+	graph := jaffleShopGraph()
+	graph.insert("stripe.payment", "finance_report")
+	graph.getOrCreate("isolated")
+
+	counts := graph.LeafSourceCounts()
+	expected := map[string]int{
+		"weekly_jaffle_metrics": 4,
+		"finance_report":        1,
+	}
+	if len(counts) != len(expected) {
+		t.Fatalf("Leaf count mismatch. Expected %v, Found %v", expected, counts)
+	}
+	for leaf, want := range expected {
+		if counts[leaf] != want {
+			t.Fatalf("Source count mismatch for %s. Expected %d, Found %d", leaf, want, counts[leaf])
+		}
+	}
+}
+
+// TestSourceLeafMatrix asserts that all four sources of the
+// jaffle_shop graph reach weekly_jaffle_metrics.
+func TestSourceLeafMatrix(t *testing.T) {
+	graph := jaffleShopGraph()
+	graph.insert("stripe.payment", "finance_report")
+	graph.getOrCreate("isolated")
+
+	sources, leaves, reaches := graph.SourceLeafMatrix()
+	expected := "gsheets.goals,jaffle_shop.customers,jaffle_shop.orders,stripe.payment"
+	if result := strings.Join(sources, ","); result != expected {
+		t.Fatalf("Sources mismatch. Expected %v, Found %v", expected, result)
+	}
+	if result := strings.Join(leaves, ","); result != "finance_report,weekly_jaffle_metrics" {
+		t.Fatalf("Leaves mismatch. Expected %v, Found %v", "finance_report,weekly_jaffle_metrics", result)
+	}
+	for i, source := range sources {
+		if !reaches[i][1] {
+			t.Fatalf("Expected %s to reach weekly_jaffle_metrics", source)
+		}
+		if reaches[i][0] != (source == "stripe.payment") {
+			t.Fatalf("Reach mismatch for %s to finance_report. Found %t", source, reaches[i][0])
+		}
+	}
+}
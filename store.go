@@ -0,0 +1,228 @@
+package graph
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Store holds the relations of a graph, so that a graph that does not
+// fit in memory can be kept on disk. A *Graph is the in-memory store,
+// and FileStore keeps the relations in a directory.
+type Store interface {
+	// Get returns the immediate upstream and downstream relations of
+	// the path, and false if the path has no relations.
+	Get(path string) (upstream, downstream []string, ok bool, err error)
+	// Put stores the relation from the upstream path to the
+	// downstream path. Putting an existing relation has no effect.
+	Put(from, to string) error
+}
+
+// Get returns copies of the immediate relations of the path.
+func (g *Graph) Get(path string) (upstream, downstream []string, ok bool, err error) {
+	node, ok := g.nodes[path]
+	if !ok {
+		return nil, nil, false, nil
+	}
+	return append([]string{}, node.upstream...), append([]string{}, node.downstream...), true, nil
+}
+
+// Put inserts the relation.
+func (g *Graph) Put(from, to string) error {
+	g.insert(from, to)
+	return nil
+}
+
+// Returns the node for the path.
+func (g *Graph) lookup(path string) (*Node, bool, error) {
+	node, ok := g.nodes[path]
+	return node, ok, nil
+}
+
+// StoreGraph is a graph whose relations are kept in a Store rather
+// than in memory, so it only reads the nodes a traversal reaches. It
+// answers the traversals below; for every other query, load the
+// relations into a Graph.
+type StoreGraph struct {
+	store Store
+}
+
+// NewStoreGraph returns a graph that reads and writes its relations
+// through the store.
+func NewStoreGraph(store Store) *StoreGraph {
+	return &StoreGraph{store: store}
+}
+
+// Insert stores the relation from the upstream path to the downstream
+// path.
+func (s *StoreGraph) Insert(from, to string) error {
+	return s.store.Put(from, to)
+}
+
+// Get returns the immediate relations of the path from the store, and
+// false if the path has no relations.
+func (s *StoreGraph) Get(path string) (upstream, downstream []string, ok bool, err error) {
+	return s.store.Get(path)
+}
+
+// Returns the node for the path read from the store. The node is not
+// kept in memory.
+func (s *StoreGraph) lookup(path string) (*Node, bool, error) {
+	upstream, downstream, ok, err := s.store.Get(path)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return &Node{path: path, upstream: upstream, downstream: downstream}, true, nil
+}
+
+// Upstream returns all the upstream nodes of the given paths like
+// Graph.Upstream, reading every node from the store.
+func (s *StoreGraph) Upstream(paths []string) ([]string, error) {
+	return s.UpstreamN(paths, -1)
+}
+
+// Downstream returns all the downstream nodes of the given paths like
+// Graph.Downstream, reading every node from the store.
+func (s *StoreGraph) Downstream(paths []string) ([]string, error) {
+	return s.DownstreamN(paths, -1)
+}
+
+// UpstreamN gets the upstream nodes of the given paths within depth
+// hops like Graph.UpstreamN.
+func (s *StoreGraph) UpstreamN(paths []string, depth int) ([]string, error) {
+	result, _, err := traverseWith(s.lookup, paths, upstreamOf, depth)
+	return result, err
+}
+
+// DownstreamN gets the downstream nodes of the given paths within
+// depth hops like Graph.DownstreamN.
+func (s *StoreGraph) DownstreamN(paths []string, depth int) ([]string, error) {
+	result, _, err := traverseWith(s.lookup, paths, downstreamOf, depth)
+	return result, err
+}
+
+// ErrStoreClosed is returned by a FileStore that has been closed.
+var ErrStoreClosed = errors.New("file store closed")
+
+// FileStore is a Store that keeps the relations of every node in its
+// own file in a directory, so only the nodes being queried are read
+// into memory. Each file holds one quoted relation per line, prefixed
+// with "u" for upstream or "d" for downstream, and is named after the
+// SHA-256 of the path. Put only appends, and duplicates are dropped
+// when a file is read, so putting the same relation again costs disk
+// space but no extra reads. With one file per node, a graph with
+// millions of nodes needs a file system that handles as many files in
+// a single directory. It is safe to use
+// from several goroutines, but not from several processes at once.
+type FileStore struct {
+	dir    string
+	mu     sync.Mutex
+	closed bool
+}
+
+// OpenFileStore opens the store in the directory, creating the
+// directory if it does not exist. Reopening a closed store's directory
+// gives back its relations.
+func OpenFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Returns the name of the file that holds the relations of the path.
+func (s *FileStore) file(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:]))
+}
+
+// Reads the relations of the path, and false if it has no file.
+func (s *FileStore) read(path string) (upstream, downstream []string, ok bool, err error) {
+	f, err := os.Open(s.file(path))
+	if os.IsNotExist(err) {
+		return nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer f.Close()
+
+	upstream, downstream = []string{}, []string{}
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < 2 {
+			return nil, nil, false, fmt.Errorf("invalid relation %q for path %s", line, path)
+		}
+		relation, err := strconv.Unquote(line[1:])
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("invalid relation %q for path %s - %w", line, path, err)
+		}
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		switch line[0] {
+		case 'u':
+			upstream = append(upstream, relation)
+		case 'd':
+			downstream = append(downstream, relation)
+		default:
+			return nil, nil, false, fmt.Errorf("invalid relation %q for path %s", line, path)
+		}
+	}
+	return upstream, downstream, true, scanner.Err()
+}
+
+// Appends a relation of the given direction to the file of the path.
+func (s *FileStore) append(path string, direction byte, relation string) error {
+	f, err := os.OpenFile(s.file(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(string(direction) + strconv.Quote(relation) + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Get returns the immediate relations of the path read from its file.
+func (s *FileStore) Get(path string) (upstream, downstream []string, ok bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, nil, false, ErrStoreClosed
+	}
+	return s.read(path)
+}
+
+// Put records the relation in the files of both paths.
+func (s *FileStore) Put(from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrStoreClosed
+	}
+	if err := s.append(from, 'd', to); err != nil {
+		return err
+	}
+	return s.append(to, 'u', from)
+}
+
+// Close closes the store. Calls after Close return ErrStoreClosed, and
+// the directory can be opened again with OpenFileStore.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
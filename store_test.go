@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestFileStore builds a disk-backed jaffle_shop graph, closes and
+// reopens the store, and asserts that the queries still match the
+// in-memory graph.
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unable to open store %s - %v", dir, err)
+	}
+	expected := jaffleShopGraph()
+	graph := NewStoreGraph(store)
+	for _, edge := range expected.edges() {
+		if err := graph.Insert(edge.From, edge.To); err != nil {
+			t.Fatalf("Error inserting edge - %v", err)
+		}
+	}
+	// inserting an edge again does not duplicate it
+	if err := graph.Insert("stg_orders", "fct_orders"); err != nil {
+		t.Fatalf("Error inserting edge - %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Error closing store - %v", err)
+	}
+	if _, _, _, err := store.Get("stg_orders"); !errors.Is(err, ErrStoreClosed) {
+		t.Fatalf("Expected ErrStoreClosed, Found %v", err)
+	}
+
+	store, err = OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unable to reopen store %s - %v", dir, err)
+	}
+	defer store.Close()
+	graph = NewStoreGraph(store)
+
+	sorted := func(paths []string, err error) string {
+		if err != nil {
+			t.Fatalf("Error traversing graph - %v", err)
+		}
+		sort.Strings(paths)
+		return strings.Join(paths, ",")
+	}
+	for _, path := range expected.sortedPaths() {
+		want := sorted(expected.Upstream([]string{path}))
+		if found := sorted(graph.Upstream([]string{path})); found != want {
+			t.Fatalf("Upstream mismatch for %s. Expected %v, Found %v", path, want, found)
+		}
+		want = sorted(expected.Downstream([]string{path}))
+		if found := sorted(graph.Downstream([]string{path})); found != want {
+			t.Fatalf("Downstream mismatch for %s. Expected %v, Found %v", path, want, found)
+		}
+	}
+	_, downstream, ok, err := graph.Get("stg_orders")
+	if err != nil || !ok {
+		t.Fatalf("Error getting stg_orders - %v", err)
+	}
+	sort.Strings(downstream)
+	if found := strings.Join(downstream, ","); found != "dim_customers,fct_orders" {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", "dim_customers,fct_orders", found)
+	}
+	if _, err := graph.Downstream([]string{"missing"}); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"sort"
+	"time"
+)
+
+// Checks if the attributes are valid at the given time. ValidFrom is
+// inclusive and ValidTo is exclusive.
+func (a *EdgeAttrs) validAt(t time.Time) bool {
+	if !a.ValidFrom.IsZero() && t.Before(a.ValidFrom) {
+		return false
+	}
+	return a.ValidTo.IsZero() || t.Before(a.ValidTo)
+}
+
+// InsertValid inserts the relation and records the interval in which
+// it is valid, from validFrom up to but excluding validTo. A zero time
+// leaves that side of the interval open. Edges inserted without an
+// interval are always valid.
+func (g *Graph) InsertValid(from, to string, validFrom, validTo time.Time) {
+	g.insert(from, to)
+	attrs := g.edgeAttrs(from, to)
+	attrs.ValidFrom, attrs.ValidTo = validFrom, validTo
+}
+
+// DownstreamAsOf gets all the downstream nodes for the given paths
+// like downstream, but only follows the edges that are valid at t.
+// The result is sorted.
+func (g *Graph) DownstreamAsOf(paths []string, t time.Time) ([]string, error) {
+	// follow only the downstream relations whose edges are valid at t
+	valid := func(n *Node) []string {
+		downstream := make([]string, 0, len(n.downstream))
+		for _, ds := range n.downstream {
+			if attrs, ok := g.attrs[Edge{From: n.path, To: ds}]; ok && !attrs.validAt(t) {
+				continue
+			}
+			downstream = append(downstream, ds)
+		}
+		return downstream
+	}
+	result, _, err := g.traverse(paths, valid, -1)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDownstreamAsOf bounds edges by timestamps and checks that the
+// reachable set changes when an edge becomes valid.
+func TestDownstreamAsOf(t *testing.T) {
+	migrated := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	graph := &Graph{}
+	graph.insert("jaffle_shop.orders", "stg_orders")
+	graph.InsertValid("stg_orders", "fct_orders", migrated, time.Time{})
+	graph.InsertValid("stg_orders", "orders_legacy", time.Time{}, migrated)
+	graph.insert("fct_orders", "weekly_jaffle_metrics")
+
+	tests := []struct {
+		at       time.Time
+		expected string
+	}{
+		{migrated.Add(-time.Hour), "orders_legacy,stg_orders"},
+		{migrated, "fct_orders,stg_orders,weekly_jaffle_metrics"},
+	}
+	for _, test := range tests {
+		downstream, err := graph.DownstreamAsOf([]string{"jaffle_shop.orders"}, test.at)
+		if err != nil {
+			t.Fatalf("Error getting downstream as of %v - %v", test.at, err)
+		}
+		if result := strings.Join(downstream, ","); result != test.expected {
+			t.Fatalf("Downstream as of %v mismatch. Expected %v, Found %v", test.at, test.expected, result)
+		}
+	}
+
+	if _, err := graph.DownstreamAsOf([]string{"missing"}, migrated); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
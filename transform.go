@@ -0,0 +1,348 @@
+package graph
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Trim returns a new graph with only the nodes within up hops upstream
+// and down hops downstream of the focus node, along with the edges
+// between them. A negative bound does not limit that direction.
+func (g *Graph) Trim(focus string, up, down int) (*Graph, error) {
+	if _, ok := g.nodes[focus]; !ok {
+		return nil, &MissingNodeError{path: focus}
+	}
+	upstream, err := g.distances([]string{focus}, upstreamOf, up)
+	if err != nil {
+		return nil, err
+	}
+	downstream, err := g.distances([]string{focus}, downstreamOf, down)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := map[string]bool{focus: true}
+	for path := range upstream {
+		keep[path] = true
+	}
+	for path := range downstream {
+		keep[path] = true
+	}
+	return g.induced(keep), nil
+}
+
+// induced returns a new graph with the kept nodes and the edges of the
+// graph between them. Nodes are added in path order so that the new
+// graph is built the same way every time.
+func (g *Graph) induced(keep map[string]bool) *Graph {
+	paths := make([]string, 0, len(keep))
+	for path := range keep {
+		if _, ok := g.nodes[path]; ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	graph := &Graph{}
+	for _, path := range paths {
+		graph.getOrCreate(path)
+		for _, ds := range g.nodes[path].downstream {
+			if keep[ds] {
+				graph.insert(path, ds)
+				graph.copyAttrs(g, Edge{From: path, To: ds})
+			}
+		}
+	}
+	return graph
+}
+
+// GroupBy rolls the graph up into a new graph with one node per group
+// key, where keyOf maps every path to its group. Edges between nodes of
+// different groups become a single edge between the groups, and edges
+// within a group are dropped.
+func (g *Graph) GroupBy(keyOf func(path string) string) *Graph {
+	graph := &Graph{}
+	for _, path := range g.sortedPaths() {
+		from := keyOf(path)
+		graph.getOrCreate(from)
+		for _, ds := range g.nodes[path].downstream {
+			if to := keyOf(ds); to != from {
+				graph.insert(from, to)
+			}
+		}
+	}
+	return graph
+}
+
+// CollapseChains returns a new graph where every maximal chain of
+// nodes with exactly one upstream and one downstream is replaced by a
+// single edge between the nodes at either end of the chain. Roots,
+// leaves and branching nodes are preserved. A cycle made only of such
+// nodes, including a node with an edge to itself, has no end to keep
+// and is collapsed to its first path with an edge to itself. Edges
+// that are not collapsed keep their attributes.
+func (g *Graph) CollapseChains() *Graph {
+	inChain := func(node *Node) bool {
+		return len(node.upstream) == 1 && len(node.downstream) == 1
+	}
+
+	graph := &Graph{}
+	collapsed := make(map[string]bool)
+	for _, path := range g.sortedPaths() {
+		node := g.nodes[path]
+		if inChain(node) {
+			continue
+		}
+		graph.getOrCreate(path)
+		for _, ds := range node.downstream {
+			if !inChain(g.nodes[ds]) {
+				graph.insert(path, ds)
+				graph.copyAttrs(g, Edge{From: path, To: ds})
+				continue
+			}
+			// follow the chain to the first node that is kept
+			for inChain(g.nodes[ds]) {
+				collapsed[ds] = true
+				ds = g.nodes[ds].downstream[0]
+			}
+			graph.insert(path, ds)
+		}
+	}
+
+	// chain nodes that no kept node leads to are on isolated cycles
+	for _, path := range g.sortedPaths() {
+		if collapsed[path] || !inChain(g.nodes[path]) {
+			continue
+		}
+		ds := path
+		for {
+			collapsed[ds] = true
+			if ds = g.nodes[ds].downstream[0]; ds == path {
+				break
+			}
+		}
+		graph.insert(path, path)
+		if g.hasEdge(path, path) {
+			graph.copyAttrs(g, Edge{From: path, To: path})
+		}
+	}
+	return graph
+}
+
+// PruneUnreachable returns a new graph with only the given roots and
+// the nodes downstream of them, along with the edges between them.
+// Nodes fed only by other sources are dropped.
+func (g *Graph) PruneUnreachable(roots []string) (*Graph, error) {
+	downstream, err := g.downstream(roots)
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[string]bool, len(roots)+len(downstream))
+	for _, path := range roots {
+		keep[path] = true
+	}
+	for _, path := range downstream {
+		keep[path] = true
+	}
+	return g.induced(keep), nil
+}
+
+// InducedEdges returns the edges of the graph whose endpoints are both
+// among the given nodes, sorted by source and target. Unknown paths
+// are ignored.
+func (g *Graph) InducedEdges(nodes []string) []Edge {
+	keep := make(map[string]bool, len(nodes))
+	for _, path := range nodes {
+		keep[path] = true
+	}
+	return g.induced(keep).edges()
+}
+
+// FilterEdges returns a new graph with all the nodes of the graph and
+// only the edges for which keep returns true.
+func (g *Graph) FilterEdges(keep func(from, to string) bool) *Graph {
+	graph := &Graph{}
+	for _, path := range g.sortedPaths() {
+		graph.getOrCreate(path)
+		for _, ds := range g.nodes[path].downstream {
+			if keep(path, ds) {
+				graph.insert(path, ds)
+				graph.copyAttrs(g, Edge{From: path, To: ds})
+			}
+		}
+	}
+	return graph
+}
+
+// CommonPrefix returns the longest prefix shared by every path in the
+// graph that ends with a separator (":", "." or "/"), so that stripping
+// it never splits a name. Returns an empty string if there is none.
+func (g *Graph) CommonPrefix() string {
+	paths := g.sortedPaths()
+	if len(paths) == 0 {
+		return ""
+	}
+	// the common prefix of sorted paths is that of the first and last
+	first, last := paths[0], paths[len(paths)-1]
+	n := 0
+	for n < len(first) && n < len(last) && first[n] == last[n] {
+		n++
+	}
+	return first[:strings.LastIndexAny(first[:n], ":./")+1]
+}
+
+// StripPrefix returns a new graph where the prefix is removed from
+// every path that has it, for display. To keep paths unique, a path
+// keeps its full name if stripping it would leave it empty or would
+// collide with another path of the graph or another stripped path.
+func (g *Graph) StripPrefix(prefix string) *Graph {
+	claims := make(map[string]int)
+	for path := range g.nodes {
+		if strings.HasPrefix(path, prefix) {
+			claims[strings.TrimPrefix(path, prefix)]++
+		}
+	}
+	names := make(map[string]string, len(g.nodes))
+	for path := range g.nodes {
+		names[path] = path
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		stripped := strings.TrimPrefix(path, prefix)
+		if _, exists := g.nodes[stripped]; stripped != "" && !exists && claims[stripped] == 1 {
+			names[path] = stripped
+		}
+	}
+
+	graph := &Graph{}
+	for _, path := range g.sortedPaths() {
+		from := names[path]
+		graph.getOrCreate(from)
+		for _, ds := range g.nodes[path].downstream {
+			to := names[ds]
+			graph.insert(from, to)
+			if attrs, ok := g.attrs[Edge{From: path, To: ds}]; ok {
+				*graph.edgeAttrs(from, to) = *attrs.clone()
+			}
+		}
+	}
+	return graph
+}
+
+// PeelLeaves returns a new graph with the leaves removed the given
+// number of times. Each round removes every node without downstream
+// relations left, which exposes their upstream nodes as the leaves of
+// the next round. Nodes on a cycle are never peeled.
+func (g *Graph) PeelLeaves(rounds int) *Graph {
+	remaining := make(map[string]int, len(g.nodes))
+	leaves := []string{}
+	for path, node := range g.nodes {
+		remaining[path] = len(node.downstream)
+		if len(node.downstream) == 0 {
+			leaves = append(leaves, path)
+		}
+	}
+	for round := 0; round < rounds && len(leaves) > 0; round++ {
+		next := []string{}
+		for _, leaf := range leaves {
+			delete(remaining, leaf)
+		}
+		for _, leaf := range leaves {
+			for _, up := range g.nodes[leaf].upstream {
+				if _, ok := remaining[up]; !ok {
+					continue
+				}
+				remaining[up]--
+				if remaining[up] == 0 {
+					next = append(next, up)
+				}
+			}
+		}
+		leaves = next
+	}
+
+	keep := make(map[string]bool, len(remaining))
+	for path := range remaining {
+		keep[path] = true
+	}
+	return g.induced(keep)
+}
+
+// PruneByDegree returns a new graph without the nodes whose total
+// degree, upstream plus downstream relations, is below minDegree.
+// Degrees are taken from the original graph in a single pass, so
+// pruning does not cascade to nodes that lose relations. The edges of
+// a pruned node are dropped rather than spliced across it, since the
+// pruned nodes are the periphery and rarely sit between kept nodes.
+func (g *Graph) PruneByDegree(minDegree int) *Graph {
+	keep := make(map[string]bool, len(g.nodes))
+	for path, node := range g.nodes {
+		if len(node.upstream)+len(node.downstream) >= minDegree {
+			keep[path] = true
+		}
+	}
+	return g.induced(keep)
+}
+
+// AncestorGraph returns a new graph with the node, all of its upstream
+// ancestors and the edges between them.
+func (g *Graph) AncestorGraph(path string) (*Graph, error) {
+	upstream, err := g.upstream([]string{path})
+	if err != nil {
+		return nil, err
+	}
+	keep := map[string]bool{path: true}
+	for _, up := range upstream {
+		keep[up] = true
+	}
+	return g.induced(keep), nil
+}
+
+// SampleSubgraph returns a new graph with about n nodes of the graph
+// and the edges between them, for previews of graphs too large to
+// render. Nodes are collected breadth first, along relations in either
+// direction, from roots picked at random until n nodes are reached, so
+// the sample is mostly connected. Once the roots run out, the walk
+// continues from the nodes not yet sampled in random order, so that
+// components without a root, such as an isolated cycle, are sampled
+// too. The same seed gives the same sample.
+func (g *Graph) SampleSubgraph(n int, seed int64) *Graph {
+	roots, others := []string{}, []string{}
+	for _, path := range g.sortedPaths() {
+		if len(g.nodes[path].upstream) == 0 {
+			roots = append(roots, path)
+		} else {
+			others = append(others, path)
+		}
+	}
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(roots), func(i, j int) { roots[i], roots[j] = roots[j], roots[i] })
+	rng.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+	starts := append(roots, others...)
+
+	keep := make(map[string]bool, n)
+	for _, start := range starts {
+		if len(keep) >= n {
+			break
+		}
+		if keep[start] {
+			continue
+		}
+		keep[start] = true
+		queue := []string{start}
+		for len(queue) > 0 && len(keep) < n {
+			node := g.nodes[queue[0]]
+			queue = queue[1:]
+			for _, neighbors := range [][]string{node.downstream, node.upstream} {
+				for _, nb := range neighbors {
+					if len(keep) < n && !keep[nb] {
+						keep[nb] = true
+						queue = append(queue, nb)
+					}
+				}
+			}
+		}
+	}
+	return g.induced(keep)
+}
@@ -0,0 +1,377 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// edgeList returns the sorted edges of the graph formatted as
+// "from->to" and joined by commas.
+func edgeList(graph *Graph) string {
+	edges := []string{}
+	for path, node := range graph.nodes {
+		for _, ds := range node.downstream {
+			edges = append(edges, path+"->"+ds)
+		}
+	}
+	sort.Strings(edges)
+	return strings.Join(edges, ",")
+}
+
+// TestTrim trims the graph around fct_orders to a single hop in each
+// direction and checks the remaining nodes and edges.
+func TestTrim(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	trimmed, err := graph.Trim("fct_orders", 1, 1)
+	if err != nil {
+		t.Fatalf("Error trimming graph - %v", err)
+	}
+	nodes := strings.Join(trimmed.sortedPaths(), ",")
+	expected := "fct_orders,stg_orders,stg_payments,weekly_jaffle_metrics"
+	if nodes != expected {
+		t.Fatalf("Node mismatch. Expected %v, Found %v", expected, nodes)
+	}
+
+	// stg_orders keeps only the edge into the trimmed region
+	downstream := strings.Join(trimmed.nodes["stg_orders"].downstream, ",")
+	if downstream != "fct_orders" {
+		t.Fatalf("Downstream relations mismatch. Expected %v, Found %v", "fct_orders", downstream)
+	}
+	// the original graph is untouched
+	if len(graph.nodes) != 10 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 10, len(graph.nodes))
+	}
+
+	if _, err := graph.Trim("missing", 1, 1); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestGroupBy rolls up the jaffle_shop graph by schema and checks the
+// edges between the schemas.
+func TestGroupBy(t *testing.T) {
+	graph := jaffleShopGraph()
+	// an edge within the jaffle_shop schema is dropped
+	graph.insert("jaffle_shop.orders", "jaffle_shop.orders_snapshot")
+
+	// group raw tables by their schema and models by their layer
+	schemaOf := func(path string) string {
+		if i := strings.Index(path, "."); i >= 0 {
+			return path[:i]
+		}
+		return path[:strings.Index(path, "_")]
+	}
+	grouped := graph.GroupBy(schemaOf)
+
+	nodes := strings.Join(grouped.sortedPaths(), ",")
+	expectedNodes := "dim,fct,gsheets,jaffle_shop,stg,stripe,weekly"
+	if nodes != expectedNodes {
+		t.Fatalf("Node mismatch. Expected %v, Found %v", expectedNodes, nodes)
+	}
+	edges := edgeList(grouped)
+	expectedEdges := "dim->weekly,fct->weekly,gsheets->weekly,jaffle_shop->stg,stg->dim,stg->fct,stripe->stg"
+	if edges != expectedEdges {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expectedEdges, edges)
+	}
+}
+
+// TestCollapseChains asserts that linear chains collapse to a single
+// edge while branching nodes are preserved.
+func TestCollapseChains(t *testing.T) {
+	chain := &Graph{}
+	for _, edge := range []Edge{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "e"}} {
+		chain.insert(edge.From, edge.To)
+	}
+	collapsed := chain.CollapseChains()
+	if edges := edgeList(collapsed); edges != "a->e" {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", "a->e", edges)
+	}
+
+	// x, y -> m -> p -> q -> r -> s, t
+	branching := &Graph{}
+	for _, edge := range []Edge{{"x", "m"}, {"y", "m"}, {"m", "p"}, {"p", "q"}, {"q", "r"}, {"r", "s"}, {"r", "t"}} {
+		branching.insert(edge.From, edge.To)
+	}
+	collapsed = branching.CollapseChains()
+	expected := "m->r,r->s,r->t,x->m,y->m"
+	if edges := edgeList(collapsed); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+
+	// the jaffle_shop graph has chains through its staging models
+	collapsed = jaffleShopGraph().CollapseChains()
+	if _, ok := collapsed.nodes["stg_payments"]; ok {
+		t.Fatalf("Expected stg_payments to be collapsed")
+	}
+	if !contains(collapsed.nodes["stripe.payment"].downstream, "fct_orders") {
+		t.Fatalf("Expected edge stripe.payment->fct_orders")
+	}
+
+	// isolated cycles keep a single node with an edge to itself, and
+	// edges that are not collapsed keep their attributes
+	cycles := &Graph{}
+	cycles.insert("x", "y")
+	cycles.insert("y", "z")
+	cycles.insert("z", "x")
+	cycles.InsertKind("w", "w", "refresh")
+	cycles.InsertKind("a", "b", "reads")
+	cycles.insert("a", "c")
+	collapsed = cycles.CollapseChains()
+	expected = "a->b,a->c,w->w,x->x"
+	if edges := edgeList(collapsed); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+	for _, edge := range []Edge{{"a", "b"}, {"w", "w"}} {
+		if attrs, ok := collapsed.attrs[edge]; !ok || len(attrs.Kinds) != 1 {
+			t.Fatalf("Expected the kind of %v to be kept", edge)
+		}
+	}
+}
+
+// TestPruneUnreachable prunes the graph to the lineage of
+// jaffle_shop.orders.
+func TestPruneUnreachable(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	pruned, err := graph.PruneUnreachable([]string{"jaffle_shop.orders"})
+	if err != nil {
+		t.Fatalf("Error pruning graph - %v", err)
+	}
+	edges := edgeList(pruned)
+	expected := "dim_customers->weekly_jaffle_metrics,fct_orders->weekly_jaffle_metrics," +
+		"jaffle_shop.orders->stg_orders,stg_orders->dim_customers,stg_orders->fct_orders"
+	if edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+	if len(pruned.nodes) != 5 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 5, len(pruned.nodes))
+	}
+
+	if _, err := graph.PruneUnreachable([]string{"missing"}); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestInducedEdges asserts that only edges with both endpoints in the
+// node set are returned.
+func TestInducedEdges(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	edges := graph.InducedEdges([]string{"weekly_jaffle_metrics", "stg_orders", "fct_orders", "missing"})
+	expected := []Edge{
+		{From: "fct_orders", To: "weekly_jaffle_metrics"},
+		{From: "stg_orders", To: "fct_orders"},
+	}
+	if len(edges) != len(expected) {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+	for i := range expected {
+		if edges[i] != expected[i] {
+			t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+		}
+	}
+}
+
+// TestFilterEdges drops all edges into weekly_jaffle_metrics and checks
+// that the node is kept while the rest of the lineage is intact.
+func TestFilterEdges(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	filtered := graph.FilterEdges(func(from, to string) bool {
+		return to != "weekly_jaffle_metrics"
+	})
+	if len(filtered.nodes) != 10 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 10, len(filtered.nodes))
+	}
+	if node := filtered.nodes["weekly_jaffle_metrics"]; len(node.upstream) != 0 || len(node.downstream) != 0 {
+		t.Fatalf("Expected weekly_jaffle_metrics to be isolated")
+	}
+	expected := "jaffle_shop.customers->stg_customers,jaffle_shop.orders->stg_orders," +
+		"stg_customers->dim_customers,stg_orders->dim_customers,stg_orders->fct_orders," +
+		"stg_payments->fct_orders,stripe.payment->stg_payments"
+	if edges := edgeList(filtered); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+}
+
+// TestStripPrefix asserts the common prefix of the dbt nodes of the
+// CSV fixture and that stripping it preserves structure and keeps
+// paths unique.
+func TestStripPrefix(t *testing.T) {
+	filename := "synq-lineage.csv"
+	graph, err := NewGraphFromCsv(filename)
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+	// the fixture mixes paths of several systems
+	if prefix := graph.CommonPrefix(); prefix != "" {
+		t.Fatalf("Common prefix mismatch. Expected %q, Found %q", "", prefix)
+	}
+
+	keep := make(map[string]bool)
+	for path := range graph.nodes {
+		if strings.HasPrefix(path, "dbt-sh-") {
+			keep[path] = true
+		}
+	}
+	dbt := graph.induced(keep)
+	expected := "dbt-sh-d577b364-a867-11ed-b4b2-fe8020e7ba25::"
+	prefix := dbt.CommonPrefix()
+	if !strings.HasPrefix(prefix, expected) {
+		t.Fatalf("Common prefix mismatch. Expected %q, Found %q", expected, prefix)
+	}
+
+	stripped := dbt.StripPrefix(prefix)
+	if stripped.Stats() != dbt.Stats() {
+		t.Fatalf("Stats mismatch. Expected %v, Found %v", dbt.Stats(), stripped.Stats())
+	}
+	for path := range stripped.nodes {
+		if strings.HasPrefix(path, prefix) {
+			t.Fatalf("Found unstripped path %s", path)
+		}
+	}
+	restored := stripped.GroupBy(func(path string) string { return prefix + path })
+	if equal, details := restored.Compare(dbt); !equal {
+		t.Fatalf("Expected stripping to preserve structure, Found differences\n%s", details)
+	}
+
+	// a stripped path that collides with an existing one is kept
+	collide := &Graph{}
+	collide.insert("dbt::stg_orders", "dbt::fct_orders")
+	collide.insert("dbt::fct_orders", "weekly_jaffle_metrics")
+	collide.insert("fct_orders", "weekly_jaffle_metrics")
+	expected = "dbt::fct_orders->weekly_jaffle_metrics,fct_orders->weekly_jaffle_metrics,stg_orders->dbt::fct_orders"
+	if edges := edgeList(collide.StripPrefix("dbt::")); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+}
+
+// TestPruneByDegree prunes the degree-1 spokes of a star graph and
+// asserts that only the hub and its well connected neighbor remain.
+func TestPruneByDegree(t *testing.T) {
+	graph := &Graph{}
+	for _, spoke := range []string{"stg_customers", "stg_orders", "stg_payments"} {
+		graph.insert(spoke, "hub")
+	}
+	graph.insert("hub", "fct_orders")
+	graph.insert("fct_orders", "weekly_jaffle_metrics")
+	graph.insert("fct_orders", "finance_report")
+
+	pruned := graph.PruneByDegree(2)
+	expected := "fct_orders,hub"
+	if found := strings.Join(pruned.sortedPaths(), ","); found != expected {
+		t.Fatalf("Node mismatch. Expected %v, Found %v", expected, found)
+	}
+	if edges := edgeList(pruned); edges != "hub->fct_orders" {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", "hub->fct_orders", edges)
+	}
+	if len(graph.nodes) != 7 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 7, len(graph.nodes))
+	}
+	if pruned := graph.PruneByDegree(0); len(pruned.nodes) != 7 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 7, len(pruned.nodes))
+	}
+}
+
+// TestPeelLeaves peels a chain and asserts that each round removes the
+// single leaf and exposes the next one.
+func TestPeelLeaves(t *testing.T) {
+	graph := &Graph{}
+	graph.insert("jaffle_shop.orders", "stg_orders")
+	graph.insert("stg_orders", "fct_orders")
+	graph.insert("fct_orders", "weekly_jaffle_metrics")
+
+	tests := []struct {
+		rounds   int
+		nodes    int
+		expected string
+	}{
+		{0, 4, "fct_orders->weekly_jaffle_metrics,jaffle_shop.orders->stg_orders,stg_orders->fct_orders"},
+		{1, 3, "jaffle_shop.orders->stg_orders,stg_orders->fct_orders"},
+		{2, 2, "jaffle_shop.orders->stg_orders"},
+		{5, 0, ""},
+	}
+	for _, test := range tests {
+		core := graph.PeelLeaves(test.rounds)
+		if edges := edgeList(core); edges != test.expected {
+			t.Fatalf("Edge mismatch after %d rounds. Expected %v, Found %v", test.rounds, test.expected, edges)
+		}
+		if len(core.nodes) != test.nodes {
+			t.Fatalf("Node count mismatch after %d rounds. Expected %d, Found %d", test.rounds, test.nodes, len(core.nodes))
+		}
+	}
+
+	// stg_orders feeds two leaves and is exposed once both are peeled
+	graph = jaffleShopGraph()
+	for rounds, peeled := range []string{"weekly_jaffle_metrics", "fct_orders", "stg_orders"} {
+		if _, ok := graph.PeelLeaves(rounds).nodes[peeled]; !ok {
+			t.Fatalf("Expected %s to remain after %d rounds", peeled, rounds)
+		}
+		if _, ok := graph.PeelLeaves(rounds + 1).nodes[peeled]; ok {
+			t.Fatalf("Expected %s to be peeled after %d rounds", peeled, rounds+1)
+		}
+	}
+}
+
+// TestAncestorGraph asserts that the ancestor graph of
+// weekly_jaffle_metrics holds all sources and intermediate models with
+// their edges, and nothing downstream of the node.
+func TestAncestorGraph(t *testing.T) {
+	graph := jaffleShopGraph()
+	graph.insert("weekly_jaffle_metrics", "exec_dashboard")
+	graph.insert("stg_payments", "fct_payments")
+
+	ancestors, err := graph.AncestorGraph("weekly_jaffle_metrics")
+	if err != nil {
+		t.Fatalf("Error getting ancestor graph - %v", err)
+	}
+	if equal, details := ancestors.Compare(jaffleShopGraph()); !equal {
+		t.Fatalf("Expected the jaffle_shop graph, Found differences\n%s", details)
+	}
+
+	if _, err := graph.AncestorGraph("missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestSampleSubgraph asserts that a sample of the CSV fixture has the
+// requested size, is a subgraph of it and is reproducible.
+func TestSampleSubgraph(t *testing.T) {
+	filename := "synq-lineage.csv"
+	graph, err := NewGraphFromCsv(filename)
+	if err != nil {
+		t.Fatalf("Unable to read input file %s - %v", filename, err)
+	}
+
+	sample := graph.SampleSubgraph(50, 42)
+	if len(sample.nodes) != 50 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 50, len(sample.nodes))
+	}
+	for _, edge := range sample.edges() {
+		if !graph.hasEdge(edge.From, edge.To) {
+			t.Fatalf("Found edge %v missing from the original graph", edge)
+		}
+	}
+	if len(sample.edges()) == 0 {
+		t.Fatalf("Expected the sample to have edges")
+	}
+	if again := graph.SampleSubgraph(50, 42); !again.Equal(sample) {
+		t.Fatalf("Expected the same sample for the same seed")
+	}
+
+	// asking for more nodes than the graph has returns all of it
+	if all := graph.SampleSubgraph(len(graph.nodes)+10, 1); !all.Equal(graph) {
+		t.Fatalf("Expected the whole graph")
+	}
+
+	// a cycle without a root is sampled once the roots run out
+	graph = &Graph{}
+	graph.insert("r", "s")
+	graph.insert("p", "q")
+	graph.insert("q", "p")
+	if sample := graph.SampleSubgraph(10, 42); len(sample.nodes) != 4 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 4, len(sample.nodes))
+	}
+}
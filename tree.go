@@ -0,0 +1,41 @@
+package graph
+
+import "sort"
+
+// TreeNode is a node in a tree expansion of the graph. Nodes shared by
+// several branches of the graph appear once under each branch.
+type TreeNode struct {
+	Path     string
+	Children []*TreeNode
+}
+
+// DownstreamTree expands the downstream of the path into a tree for
+// collapsible tree views, duplicating shared descendants under every
+// branch that reaches them. Children are sorted by path and expanded
+// up to maxDepth levels below the root; a negative maxDepth does not
+// limit the tree. A node is never expanded below itself, so cycles do
+// not recurse.
+func (g *Graph) DownstreamTree(path string, maxDepth int) (*TreeNode, error) {
+	if _, ok := g.nodes[path]; !ok {
+		return nil, &MissingNodeError{path: path}
+	}
+	onBranch := make(map[string]bool)
+	var expand func(path string, depth int) *TreeNode
+	expand = func(path string, depth int) *TreeNode {
+		tree := &TreeNode{Path: path, Children: []*TreeNode{}}
+		if depth == maxDepth {
+			return tree
+		}
+		onBranch[path] = true
+		children := append([]string{}, g.nodes[path].downstream...)
+		sort.Strings(children)
+		for _, child := range children {
+			if !onBranch[child] {
+				tree.Children = append(tree.Children, expand(child, depth+1))
+			}
+		}
+		onBranch[path] = false
+		return tree
+	}
+	return expand(path, 0), nil
+}
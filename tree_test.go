@@ -0,0 +1,50 @@
+package graph
+
+import "testing"
+
+// TestDownstreamTree asserts that the shared leaf of a diamond appears
+// under both branches.
+func TestDownstreamTree(t *testing.T) {
+	graph := &Graph{}
+	graph.insert("a", "b")
+	graph.insert("a", "c")
+	graph.insert("b", "d")
+	graph.insert("c", "d")
+
+	tree, err := graph.DownstreamTree("a", -1)
+	if err != nil {
+		t.Fatalf("Error getting downstream tree - %v", err)
+	}
+	if tree.Path != "a" || len(tree.Children) != 2 {
+		t.Fatalf("Tree mismatch. Expected a with 2 children, Found %s with %d", tree.Path, len(tree.Children))
+	}
+	for i, branch := range []string{"b", "c"} {
+		child := tree.Children[i]
+		if child.Path != branch || len(child.Children) != 1 || child.Children[0].Path != "d" {
+			t.Fatalf("Branch mismatch. Expected %s -> d, Found %v", branch, child)
+		}
+	}
+
+	// the depth limit stops the expansion
+	tree, err = graph.DownstreamTree("a", 1)
+	if err != nil {
+		t.Fatalf("Error getting downstream tree - %v", err)
+	}
+	if len(tree.Children) != 2 || len(tree.Children[0].Children) != 0 {
+		t.Fatalf("Expected a tree of depth 1")
+	}
+
+	// a cycle back to the root is not expanded again
+	graph.insert("d", "a")
+	tree, err = graph.DownstreamTree("a", -1)
+	if err != nil {
+		t.Fatalf("Error getting downstream tree - %v", err)
+	}
+	if leaf := tree.Children[0].Children[0]; len(leaf.Children) != 0 {
+		t.Fatalf("Expected the cycle to stop at d, Found %v", leaf.Children)
+	}
+
+	if _, err := graph.DownstreamTree("missing", 1); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NewGraphFromTSVStream reads lines of the form "from<TAB>to" and
+// creates a graph with an edge for every line. Fields may be separated
+// by any whitespace. Blank lines and lines starting with "#" are
+// ignored, and any other line without exactly two fields is an error.
+func NewGraphFromTSVStream(r io.Reader) (*Graph, error) {
+	graph, _, err := LoadTSVStream(r, LoadOptions{})
+	return graph, err
+}
+
+// LoadTSVStream reads lines like NewGraphFromTSVStream, applying the
+// load options to every relation. Ignored lines are not counted as
+// rows.
+func LoadTSVStream(r io.Reader, opts LoadOptions) (*Graph, LoadResult, error) {
+	result := LoadResult{}
+	graph := &Graph{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, result, fmt.Errorf("line %d has %d fields, expected 2", line, len(fields))
+		}
+		result.Rows++
+		if err := opts.insert(graph, fields[0], fields[1], &result); err != nil {
+			return nil, result, err
+		}
+		opts.progress(result.Rows, false)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, result, err
+	}
+	opts.progress(result.Rows, true)
+	return graph, result, nil
+}
@@ -0,0 +1,33 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNewGraphFromTSVStream reads lines with a comment and a blank line
+// and asserts that only the relations are inserted.
+func TestNewGraphFromTSVStream(t *testing.T) {
+	input := `# orders lineage
+jaffle_shop.orders	stg_orders
+
+stg_orders   fct_orders
+	# indented comment
+fct_orders	weekly_jaffle_metrics
+`
+	graph, err := NewGraphFromTSVStream(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unable to read input - %v", err)
+	}
+	expected := "fct_orders->weekly_jaffle_metrics,jaffle_shop.orders->stg_orders,stg_orders->fct_orders"
+	if edges := edgeList(graph); edges != expected {
+		t.Fatalf("Edge mismatch. Expected %v, Found %v", expected, edges)
+	}
+	if len(graph.nodes) != 4 {
+		t.Fatalf("Node count mismatch. Expected %d, Found %d", 4, len(graph.nodes))
+	}
+
+	if _, err := NewGraphFromTSVStream(strings.NewReader("stg_orders\n")); err == nil {
+		t.Fatalf("Expected error for line with one field")
+	}
+}
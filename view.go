@@ -0,0 +1,156 @@
+package graph
+
+import "sort"
+
+// NodeView is a read-only view of a node passed to ForEachNode.
+type NodeView struct {
+	node *Node
+}
+
+// Path returns the path of the node.
+func (v *NodeView) Path() string {
+	return v.node.path
+}
+
+// Upstream returns a sorted copy of the immediate upstream relations
+// of the node.
+func (v *NodeView) Upstream() []string {
+	upstream := append([]string{}, v.node.upstream...)
+	sort.Strings(upstream)
+	return upstream
+}
+
+// Downstream returns a sorted copy of the immediate downstream
+// relations of the node.
+func (v *NodeView) Downstream() []string {
+	downstream := append([]string{}, v.node.downstream...)
+	sort.Strings(downstream)
+	return downstream
+}
+
+// UpstreamOrdered returns a copy of the immediate upstream relations
+// of the node at the path in the order they were inserted. Returns a
+// MissingNodeError if the path is not in the graph.
+func (g *Graph) UpstreamOrdered(path string) ([]string, error) {
+	node, ok := g.nodes[path]
+	if !ok {
+		return nil, &MissingNodeError{path: path}
+	}
+	return append([]string{}, node.upstream...), nil
+}
+
+// DownstreamOrdered returns a copy of the immediate downstream
+// relations of the node at the path in the order they were inserted.
+// Returns a MissingNodeError if the path is not in the graph.
+func (g *Graph) DownstreamOrdered(path string) ([]string, error) {
+	node, ok := g.nodes[path]
+	if !ok {
+		return nil, &MissingNodeError{path: path}
+	}
+	return append([]string{}, node.downstream...), nil
+}
+
+// ForEachNode calls fn with a view of every node in the graph, in path
+// order.
+func (g *Graph) ForEachNode(fn func(n *NodeView)) {
+	for _, path := range g.sortedPaths() {
+		fn(&NodeView{node: g.nodes[path]})
+	}
+}
+
+// NodeInfo describes a node and its place in the graph, as returned by
+// Describe.
+type NodeInfo struct {
+	Path string
+	// Upstream and Downstream are the sorted immediate relations.
+	Upstream   []string
+	Downstream []string
+	InDegree   int
+	OutDegree  int
+	// Depth and Height are the longest path lengths from a root and
+	// to a leaf, see Graph.Depth and Graph.Height.
+	Depth  int
+	Height int
+	Root   bool
+	Leaf   bool
+	// UpstreamCount and DownstreamCount are the numbers of transitive
+	// upstream and downstream nodes.
+	UpstreamCount   int
+	DownstreamCount int
+}
+
+// NodeSummary holds the immediate relations of a node and what they
+// say about it, as returned by GetNode.
+type NodeSummary struct {
+	Path string
+	// Upstream and Downstream are the sorted immediate relations.
+	Upstream   []string
+	Downstream []string
+	InDegree   int
+	OutDegree  int
+	Root       bool
+	Leaf       bool
+}
+
+// GetNode returns copies of the immediate relations of a node, so they
+// can be inspected without exposing the graph's internals, along with
+// its degrees and whether it is a root or a leaf. Use Describe for the
+// fields that need a traversal.
+func (g *Graph) GetNode(path string) (NodeSummary, error) {
+	node, ok := g.nodes[path]
+	if !ok {
+		return NodeSummary{}, &MissingNodeError{path: path}
+	}
+	return node.summary(), nil
+}
+
+// Returns the summary of the node, which only needs the node itself.
+func (n *Node) summary() NodeSummary {
+	view := &NodeView{node: n}
+	return NodeSummary{
+		Path:       n.path,
+		Upstream:   view.Upstream(),
+		Downstream: view.Downstream(),
+		InDegree:   len(n.upstream),
+		OutDegree:  len(n.downstream),
+		Root:       len(n.upstream) == 0,
+		Leaf:       len(n.downstream) == 0,
+	}
+}
+
+// Describe returns everything about a node in one call, for a node
+// detail panel. Returns a CycleError if the depth or height of the
+// node cannot be computed because of a cycle.
+func (g *Graph) Describe(path string) (NodeInfo, error) {
+	node, ok := g.nodes[path]
+	if !ok {
+		return NodeInfo{}, &MissingNodeError{path: path}
+	}
+	summary := node.summary()
+	info := NodeInfo{
+		Path:       summary.Path,
+		Upstream:   summary.Upstream,
+		Downstream: summary.Downstream,
+		InDegree:   summary.InDegree,
+		OutDegree:  summary.OutDegree,
+		Root:       summary.Root,
+		Leaf:       summary.Leaf,
+	}
+	var err error
+	if info.Depth, err = g.Depth(path); err != nil {
+		return NodeInfo{}, err
+	}
+	if info.Height, err = g.Height(path); err != nil {
+		return NodeInfo{}, err
+	}
+	upstream, err := g.upstream([]string{path})
+	if err != nil {
+		return NodeInfo{}, err
+	}
+	downstream, err := g.downstream([]string{path})
+	if err != nil {
+		return NodeInfo{}, err
+	}
+	info.UpstreamCount, info.DownstreamCount = len(upstream), len(downstream)
+	return info, nil
+}
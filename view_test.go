@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestForEachNode collects the paths passed to the callback and
+// asserts that they come out sorted along with sorted neighbors.
+func TestForEachNode(t *testing.T) {
+	graph := jaffleShopGraph()
+	paths := []string{}
+	graph.ForEachNode(func(n *NodeView) {
+		paths = append(paths, n.Path())
+		if n.Path() == "weekly_jaffle_metrics" {
+			expected := "dim_customers,fct_orders,gsheets.goals"
+			if upstream := strings.Join(n.Upstream(), ","); upstream != expected {
+				t.Fatalf("Upstream mismatch. Expected %v, Found %v", expected, upstream)
+			}
+		}
+		if n.Path() == "stg_orders" {
+			if downstream := strings.Join(n.Downstream(), ","); downstream != "dim_customers,fct_orders" {
+				t.Fatalf("Downstream mismatch. Expected %v, Found %v", "dim_customers,fct_orders", downstream)
+			}
+		}
+	})
+
+	if len(paths) != 10 || !sort.StringsAreSorted(paths) {
+		t.Fatalf("Expected 10 sorted paths, Found %v", paths)
+	}
+}
+
+// TestGetNode checks the relations returned for fct_orders and that
+// changing them does not change the graph.
+func TestGetNode(t *testing.T) {
+	graph := jaffleShopGraph()
+
+	info, err := graph.GetNode("fct_orders")
+	if err != nil {
+		t.Fatalf("Error getting node - %v", err)
+	}
+	if info.Path != "fct_orders" {
+		t.Fatalf("Path mismatch. Expected %v, Found %v", "fct_orders", info.Path)
+	}
+	if upstream := strings.Join(info.Upstream, ","); upstream != "stg_orders,stg_payments" {
+		t.Fatalf("Upstream mismatch. Expected %v, Found %v", "stg_orders,stg_payments", upstream)
+	}
+	if downstream := strings.Join(info.Downstream, ","); downstream != "weekly_jaffle_metrics" {
+		t.Fatalf("Downstream mismatch. Expected %v, Found %v", "weekly_jaffle_metrics", downstream)
+	}
+	if info.InDegree != 2 || info.OutDegree != 1 || info.Root || info.Leaf {
+		t.Fatalf("Degree mismatch. Expected %v, Found %+v", "in 2, out 1, neither root nor leaf", info)
+	}
+	if root, _ := graph.GetNode("jaffle_shop.orders"); !root.Root || root.Leaf || root.OutDegree != 1 {
+		t.Fatalf("Expected jaffle_shop.orders to be a root, Found %+v", root)
+	}
+
+	info.Upstream[0] = "changed"
+	if contains(graph.nodes["fct_orders"].upstream, "changed") {
+		t.Fatalf("GetNode exposed the node's relations")
+	}
+	if _, err := graph.GetNode("missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}
+
+// TestDescribe asserts that the description of fct_orders matches the
+// individual computations.
+func TestDescribe(t *testing.T) {
+	graph := jaffleShopGraph()
+	info, err := graph.Describe("fct_orders")
+	if err != nil {
+		t.Fatalf("Error describing node - %v", err)
+	}
+
+	depth, _ := graph.Depth("fct_orders")
+	height, _ := graph.Height("fct_orders")
+	upstream, _ := graph.upstream([]string{"fct_orders"})
+	downstream, _ := graph.downstream([]string{"fct_orders"})
+	if strings.Join(info.Upstream, ",") != "stg_orders,stg_payments" || strings.Join(info.Downstream, ",") != "weekly_jaffle_metrics" {
+		t.Fatalf("Relations mismatch. Found %v and %v", info.Upstream, info.Downstream)
+	}
+	if info.InDegree != 2 || info.OutDegree != 1 || info.Root || info.Leaf {
+		t.Fatalf("Degree mismatch. Found %+v", info)
+	}
+	if info.Depth != depth || info.Height != height || depth != 2 || height != 1 {
+		t.Fatalf("Depth and height mismatch. Expected %d and %d, Found %d and %d", depth, height, info.Depth, info.Height)
+	}
+	if info.UpstreamCount != len(upstream) || info.DownstreamCount != len(downstream) {
+		t.Fatalf("Transitive counts mismatch. Expected %d and %d, Found %d and %d", len(upstream), len(downstream), info.UpstreamCount, info.DownstreamCount)
+	}
+
+	if _, err := graph.Describe("missing"); err == nil {
+		t.Fatalf("Expected error for missing node")
+	}
+}